@@ -12,30 +12,53 @@ const (
 	argHost    arg = "host"
 	argPort    arg = "port"
 	argTitle   arg = "title"
+	argVersion arg = "version"
 	argHeader  arg = "header"
 	argOutput  arg = "output"
+	argNaming  arg = "naming"
 	argschemes arg = "scheme"
+	argLog     arg = "log"
+	argLogFile arg = "logfile"
 )
 
 // argsOptions .
 type argsOptions struct {
 	args string
+	// base 配置文件中的配置. 输入参数优先
+	base *configuration
 }
 
 // newArgsParser .
-func newArgsParser(args string) parser {
-	return &argsOptions{args: args}
+func newArgsParser(args string, base *configuration) parser {
+	return &argsOptions{args: args, base: base}
 }
 
-// parse .
-func (opts *argsOptions) parse() *configuration {
+// parse 在 base 的基础上解析输入参数. 指定了 header、scheme、output 时替换配置文件中的列表
+func (opts *argsOptions) parse() (*configuration, error) {
+	var errs types.Errors
 	var conf = &configuration{
 		Header:   make([]types.Header, 0),
 		Document: make([]*Document, 0),
 	}
+	if opts.base != nil {
+		*conf = *opts.base
+	}
+
+	// reset 同一参数只在第一次出现时清空配置文件中的列表
+	var resets = make(map[arg]struct{}, 0)
+	reset := func(a arg, fn func()) {
+		if _, found := resets[a]; !found {
+			resets[a] = struct{}{}
+			fn()
+		}
+	}
 
 	if len(opts.args) != 0 {
 		for _, param := range strings.Split(opts.args, ",") {
+			if len(param) == 0 {
+				continue
+			}
+
 			var value string
 			if i := strings.Index(param, "="); i >= 0 {
 				value = param[i+1:]
@@ -49,69 +72,43 @@ func (opts *argsOptions) parse() *configuration {
 				conf.Port = value
 			case argTitle:
 				conf.Title = value
+			case argVersion:
+				conf.Version = value
 			case argHeader:
+				reset(argHeader, func() { conf.Header = make([]types.Header, 0) })
 				conf.Header = append(conf.Header, types.Header(value))
-			case argschemes:
-				if len(conf.Schemes) == 0 {
-					conf.Schemes = make([]string, 0, 2)
+			case argNaming:
+				switch types.Naming(value) {
+				case types.NamingProto, types.NamingJson:
+					conf.Naming = types.Naming(value)
+				default:
+					errs.Append(fmt.Errorf(`invalid naming of "%s"`, value))
+				}
+			case argLog:
+				if v, err := logger.ParseLevel(value); err != nil {
+					errs.Append(err)
+				} else {
+					conf.Log = level(v)
 				}
+			case argLogFile:
+				conf.LogFile = value
+			case argschemes:
+				reset(argschemes, func() { conf.Schemes = make([]string, 0, 2) })
 				conf.Schemes = append(conf.Schemes, value)
 			case argOutput:
-				switch types.DocumentType(value) {
-				case types.DocumentTypeSwagger:
-					var filename = "swagger.json"
-					if len(conf.Title) != 0 {
-						filename = fmt.Sprintf("%s.%s", strings.ToLower(conf.Title), filename)
-					}
-
-					conf.Document = append(conf.Document, &Document{
-						Type: types.DocumentTypeSwagger,
-						File: filename,
-					})
-				case types.DocumentTypePostman:
-					var filename = "postman.json"
-					if len(conf.Title) != 0 {
-						filename = fmt.Sprintf("%s.%s", strings.ToLower(conf.Title), filename)
-					}
-
-					conf.Document = append(conf.Document, &Document{
-						Type: types.DocumentTypePostman,
-						File: filename,
-					})
-				case types.DocumentTypeHTML:
-					var filename = "apidoc.html"
-					if len(conf.Title) != 0 {
-						filename = fmt.Sprintf("%s.html", strings.ToLower(conf.Title))
-					}
-
-					conf.Document = append(conf.Document, &Document{
-						Type: types.DocumentTypeHTML,
-						File: filename,
-					})
-				case types.DocumentTypeMarkdown:
-					var filename = "apidoc.md"
-					if len(conf.Title) != 0 {
-						filename = fmt.Sprintf("%s.md", strings.ToLower(conf.Title))
-					}
-
-					conf.Document = append(conf.Document, &Document{
-						Type: types.DocumentTypeMarkdown,
-						File: filename,
-					})
-				default:
-					logger.Fatalf(`invalid type of "%s"`, value)
+				reset(argOutput, func() { conf.Document = make([]*Document, 0) })
+				if err := checkDocument(types.DocumentType(value)); err != nil {
+					errs.Append(err)
+				} else {
+					conf.Document = append(conf.Document, &Document{Type: types.DocumentType(value)})
 				}
+			case argConfigfile:
+				// 配置文件由 fileOptions 解析
+			default:
+				errs.Append(fmt.Errorf(`unknown parameter "%s"`, param))
 			}
 		}
 	}
 
-	// default document
-	if len(conf.Document) == 0 {
-		conf.Document = append(conf.Document, &Document{
-			Type: types.DocumentTypeSwagger,
-			File: "swagger.json",
-		})
-	}
-
-	return conf
+	return conf, errs.Err()
 }
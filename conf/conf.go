@@ -1,9 +1,12 @@
 package conf
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charlesbases/protoc-gen-apidoc/logger"
 	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"gopkg.in/yaml.v3"
 )
 
 type arg string
@@ -15,9 +18,31 @@ type configuration struct {
 	Host     string         `yaml:"host"`
 	Port     string         `yaml:"port"`
 	Title    string         `yaml:"title"`
+	Version  string         `yaml:"version"`
 	Header   []types.Header `yaml:"header"`
 	Schemes  []string       `yaml:"schemes"`
+	Naming   types.Naming   `yaml:"naming"`
 	Document []*Document    `yaml:"document"`
+	Log      level          `yaml:"log"`
+	LogFile  string         `yaml:"logfile"`
+}
+
+// level 日志级别. 配置文件中为 warn、info、debug
+type level logger.Level
+
+// UnmarshalYAML 返回 *yaml.TypeError, 与其他类型错误一起报告
+func (l *level) UnmarshalYAML(node *yaml.Node) error {
+	v, err := logger.ParseLevel(node.Value)
+	if err != nil {
+		return &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: %v", node.Line, err)}}
+	}
+	*l = level(v)
+	return nil
+}
+
+// Level .
+func (l level) Level() logger.Level {
+	return logger.Level(l)
 }
 
 // Document .
@@ -26,21 +51,87 @@ type Document struct {
 	File string             `yaml:"file"`
 }
 
+// checkDocument .
+func checkDocument(typ types.DocumentType) error {
+	switch typ {
+	case types.DocumentTypeSwagger, types.DocumentTypePostman, types.DocumentTypeHTML, types.DocumentTypeMarkdown:
+		return nil
+	default:
+		return fmt.Errorf(`invalid type of "%s"`, typ)
+	}
+}
+
+// defaultFile 文档的默认文件名. 指定 title 时, 文件名中包含 title
+func defaultFile(typ types.DocumentType, title string) string {
+	switch typ {
+	case types.DocumentTypeSwagger:
+		if len(title) != 0 {
+			return fmt.Sprintf("%s.swagger.json", strings.ToLower(title))
+		}
+		return "swagger.json"
+	case types.DocumentTypePostman:
+		if len(title) != 0 {
+			return fmt.Sprintf("%s.postman.json", strings.ToLower(title))
+		}
+		return "postman.json"
+	case types.DocumentTypeHTML:
+		if len(title) != 0 {
+			return fmt.Sprintf("%s.html", strings.ToLower(title))
+		}
+		return "apidoc.html"
+	case types.DocumentTypeMarkdown:
+		if len(title) != 0 {
+			return fmt.Sprintf("%s.md", strings.ToLower(title))
+		}
+		return "apidoc.md"
+	default:
+		return ""
+	}
+}
+
 // parser 配置解析器
 type parser interface {
-	parse() *configuration
+	parse() (*configuration, error)
 }
 
-// Parse .
-func Parse(args string) {
+// Parse 解析配置文件与输入参数. 输入参数优先于配置文件
+func Parse(args string) error {
+	var errs types.Errors
+
 	// 配置文件解析
-	// config = newFileParser(args).parse()
-	// 输入参数解析
-	config = newArgsParser(args).parse()
+	base, err := newFileParser(args).parse()
+	errs.Append(err)
+
+	// 输入参数解析. 与配置文件中的错误一起报告
+	config, err = newArgsParser(args, base).parse()
+	if errs.Append(err); len(errs) != 0 {
+		return errs.Err()
+	}
 
 	if len(config.Host) != 0 {
 		config.Host = strings.ToLower(config.Host)
 	}
+
+	// 默认使用 proto 字段名
+	if len(config.Naming) == 0 {
+		config.Naming = types.NamingProto
+	}
+
+	// default document
+	if len(config.Document) == 0 {
+		config.Document = append(config.Document, &Document{
+			Type: types.DocumentTypeSwagger,
+			File: "swagger.json",
+		})
+	}
+
+	// 合并之后使用最终的 title 生成默认文件名
+	for _, doc := range config.Document {
+		if len(doc.File) == 0 {
+			doc.File = defaultFile(doc.Type, config.Title)
+		}
+	}
+	return nil
 }
 
 // Get .
@@ -0,0 +1,94 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+)
+
+// configfile .
+func configfile(t *testing.T, content string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "apidoc.yaml")
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestParseMerge(t *testing.T) {
+	name := configfile(t, "title: Demo\nheader:\n  - X-Token\ndocument:\n  - type: swagger\n  - type: html\n    file: index.html\n")
+
+	tests := []struct {
+		name     string
+		args     string
+		header   []types.Header
+		document []*Document
+	}{
+		{
+			name:   "config file",
+			args:   "configfile=" + name,
+			header: []types.Header{"X-Token"},
+			document: []*Document{
+				{Type: types.DocumentTypeSwagger, File: "demo.swagger.json"},
+				{Type: types.DocumentTypeHTML, File: "index.html"},
+			},
+		},
+		{
+			name:   "title override",
+			args:   "configfile=" + name + ",title=Over",
+			header: []types.Header{"X-Token"},
+			document: []*Document{
+				{Type: types.DocumentTypeSwagger, File: "over.swagger.json"},
+				{Type: types.DocumentTypeHTML, File: "index.html"},
+			},
+		},
+		{
+			name:     "list override",
+			args:     "configfile=" + name + ",output=markdown,header=Authorization",
+			header:   []types.Header{"Authorization"},
+			document: []*Document{{Type: types.DocumentTypeMarkdown, File: "demo.md"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(Get().Header, tt.header) {
+				t.Errorf("header: got %v, want %v", Get().Header, tt.header)
+			}
+			if !reflect.DeepEqual(Get().Document, tt.document) {
+				for _, doc := range Get().Document {
+					t.Logf("document: %+v", *doc)
+				}
+				t.Errorf("document: got %d documents, want %d", len(Get().Document), len(tt.document))
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	name := configfile(t, "title: Demo\ncolour: red\nnaming: snake\n")
+
+	err := Parse("configfile=" + name + ",tilte=Over,output=pdf")
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		"field colour not found",
+		`invalid naming of "snake"`,
+		`unknown parameter "tilte"`,
+		`invalid type of "pdf"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
@@ -1,22 +1,24 @@
 package conf
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/charlesbases/protoc-gen-apidoc/types"
 	"gopkg.in/yaml.v3"
-	_ "gopkg.in/yaml.v3"
-
-	"github.com/charlesbases/protoc-gen-apidoc/logger"
 )
 
 const (
 	argConfigfile arg = "configfile"
-
-	defaultConfigfile = "apidoc.yaml"
 )
 
+// defaultConfigfiles 未指定 configfile 时, 依次查找 protoc 运行目录中的配置文件
+var defaultConfigfiles = []string{"apidoc.yaml", "apidoc.yml"}
+
 // fileOptions .
 type fileOptions struct {
 	configfile string
@@ -24,7 +26,7 @@ type fileOptions struct {
 
 // newFileParser .
 func newFileParser(args string) parser {
-	var opts = &fileOptions{configfile: defaultConfigfile}
+	var opts = new(fileOptions)
 
 	if len(args) != 0 {
 		for _, param := range strings.Split(args, ",") {
@@ -45,23 +47,73 @@ func newFileParser(args string) parser {
 	return opts
 }
 
-// parse .
-func (opts *fileOptions) parse() *configuration {
-	abspath, err := filepath.Abs(opts.configfile)
+// parse 未指定 configfile 且默认配置文件不存在时, 返回 nil
+func (opts *fileOptions) parse() (*configuration, error) {
+	var filename = opts.configfile
+	if len(filename) == 0 {
+		for _, name := range defaultConfigfiles {
+			if _, err := os.Stat(name); err == nil {
+				filename = name
+				break
+			}
+		}
+		if len(filename) == 0 {
+			return nil, nil
+		}
+	}
+
+	abspath, err := filepath.Abs(filename)
 	if err != nil {
-		logger.Fatal(err)
+		return nil, err
 	}
 
 	configfile, err := os.Open(abspath)
 	if err != nil {
-		logger.Fatal(err)
+		return nil, fmt.Errorf("open config file failed. %v", err)
 	}
 	defer configfile.Close()
 
 	var config = new(configuration)
-	if err := yaml.NewDecoder(configfile).Decode(config); err != nil {
-		logger.Fatal(err)
+
+	// 未知的配置项、类型错误时报错. 类型错误不会中断解析, 与其他配置项的错误一起报告
+	var errs types.Errors
+	var decoder = yaml.NewDecoder(configfile)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		for _, e := range typeErr.Errors {
+			errs.Append(fmt.Errorf("%s: %s", filename, e))
+		}
+	}
+
+	errs.Append(opts.check(filename, config))
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// check 校验配置项的值. document 的默认文件名在合并输入参数之后生成
+func (opts *fileOptions) check(filename string, config *configuration) error {
+	var errs types.Errors
+
+	switch config.Naming {
+	case "", types.NamingProto, types.NamingJson:
+	default:
+		errs.Append(fmt.Errorf(`%s: invalid naming of "%s"`, filename, config.Naming))
 	}
 
-	return config
+	for idx, doc := range config.Document {
+		if doc == nil {
+			errs.Append(fmt.Errorf("%s: document[%d]: empty document", filename, idx))
+			continue
+		}
+		if err := checkDocument(doc.Type); err != nil {
+			errs.Append(fmt.Errorf("%s: document[%d]: %v", filename, idx, err))
+		}
+	}
+	return errs.Err()
 }
@@ -1,14 +1,53 @@
 package encoder
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charlesbases/protoc-gen-apidoc/protoc"
 	"github.com/charlesbases/protoc-gen-apidoc/types"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
+// wellknowns google.protobuf well-known type 的 protojson 示例
+var wellknowns = map[types.WellKnownType]string{
+	types.WellKnownTimestamp: `"1970-01-01T00:00:00Z"`,
+	types.WellKnownDuration:  `"1.5s"`,
+	types.WellKnownFieldMask: `"field1,field2.subField"`,
+	types.WellKnownStruct:    `{}`,
+	types.WellKnownValue:     `"value"`,
+	types.WellKnownListValue: `[]`,
+	types.WellKnownEmpty:     `{}`,
+	types.WellKnownAny:       `{"@type": "type.googleapis.com/google.protobuf.Empty"}`,
+
+	// wrappers
+	types.WellKnownBoolValue:   `false`,
+	types.WellKnownBytesValue:  `"string"`,
+	types.WellKnownDoubleValue: `0`,
+	types.WellKnownFloatValue:  `0`,
+	types.WellKnownInt32Value:  `0`,
+	types.WellKnownInt64Value:  `0`,
+	types.WellKnownStringValue: `"string"`,
+	types.WellKnownUInt32Value: `0`,
+	types.WellKnownUInt64Value: `0`,
+}
+
+// formats validate string format 的示例
+var formats = map[string]string{
+	"email":         "user@example.com",
+	"hostname":      "example.com",
+	"address":       "example.com",
+	"ip":            "127.0.0.1",
+	"ipv4":          "127.0.0.1",
+	"ipv6":          "::1",
+	"uri":           "https://example.com",
+	"uri-reference": "/example",
+	"uuid":          "00000000-0000-0000-0000-000000000000",
+}
+
 // encoder .
 type encoder struct {
 	p *types.Package
@@ -24,10 +63,24 @@ func NewEncoder(p *types.Package) *encoder {
 
 // EncodeJson .
 func (e *encoder) EncodeJson(messName string) string {
-	if mess, found := e.p.MessageDic[messName]; found && len(mess.Fields) != 0 {
+	if mess, found := e.p.MessageDic[messName]; found {
+		if len(mess.Metadata.Example) != 0 {
+			return mess.Metadata.ExampleJson()
+		}
+		return e.encodeJson(mess)
+	}
+	if wkt, found := types.WellKnown(messName); found {
+		return wellknowns[wkt]
+	}
+	return ""
+}
+
+// encodeJson . excludes 为不需要的字段路径
+func (e *encoder) encodeJson(mess *types.Message, excludes ...string) string {
+	if len(mess.Fields) != 0 {
 		var br strings.Builder
 		br.WriteString("{\n")
-		br.WriteString(e.encodeMessage(mess, 1))
+		br.WriteString(e.encodeMessage(mess, 1, excludes...))
 		br.WriteString("\n}")
 		return br.String()
 	}
@@ -39,103 +92,255 @@ func (e *encoder) indent(layer int) string {
 	return strings.Repeat("  ", layer)
 }
 
-// encodeMessage .
-func (e *encoder) encodeMessage(mess *types.Message, layer int) string {
-	indent := e.indent(layer)
+// EncodeBody http body 示例. body: "*" 为整个请求结构, "" 为无 body, 其他为请求结构中的字段名.
+// excludes 为不在 body 中的字段路径, 例如 path 参数. 嵌套字段以 "." 连接, 例: user.user_id
+func (e *encoder) EncodeBody(messName string, body string, excludes ...string) string {
+	switch body {
+	case "":
+		return ""
+	case "*":
+		if mess, found := e.p.MessageDic[messName]; found {
+			// oneof 中的字段在 body 之外时, 同一 group 中的其他字段也不在 body 中
+			var oneofs = make([]string, 0)
+			for _, field := range mess.Fields {
+				if len(field.ProtoOneof) != 0 && types.Contains(excludes, field.ProtoName) {
+					oneofs = append(oneofs, field.ProtoOneof)
+				}
+			}
 
-	var br strings.Builder
-	for idx, field := range mess.Fields {
-		switch field.JsonType {
-		case types.JsonType_Object:
-			switch field.ProtoType {
-			case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
-				value := e.encodeEnum(field.ProtoTypeName)
-
-				switch field.JsonLabel {
-				case types.JsonLabel_Repeated:
-					br.WriteString(fmt.Sprintf(`%s"%s": [`, indent, field.JsonName))
-					br.WriteString(fmt.Sprintf("\n%s%v\n", e.indent(layer+1), value))
-					br.WriteString(fmt.Sprintf("%s]", indent))
-				default:
-					br.WriteString(fmt.Sprintf(`%s"%s": "%s"`, indent, field.JsonName, value))
+			var fields = make([]*types.MessageField, 0, len(mess.Fields))
+			for _, field := range mess.Fields {
+				if !types.Contains(excludes, field.ProtoName) && !types.Contains(oneofs, field.ProtoOneof) {
+					fields = append(fields, field)
 				}
-			case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
-				// 预防同名结构体嵌套导致 goroutine 堆栈字节溢出
-				if nesteds := fmt.Sprintf("%s.%s", field.MessageName, field.ProtoName); e.nesteds[nesteds] == 2 {
-					br.WriteString(fmt.Sprintf(`%s"%s": null`, indent, field.JsonName))
-					goto ending
-				} else {
-					e.nesteds[nesteds]++
+			}
+			return e.encodeJson(&types.Message{Name: mess.Name, Description: mess.Description, Fields: fields}, excludes...)
+		}
+		return ""
+	default:
+		if mess, found := e.p.MessageDic[messName]; found {
+			for _, field := range mess.Fields {
+				if field.ProtoName == body {
+					return e.encodeField(field, 0, types.SubPaths(excludes, body)...)
 				}
+			}
+		}
+		return ""
+	}
+}
 
-				if nesteds, found := e.p.MessageDic[field.ProtoTypeName]; found {
-					switch field.JsonLabel {
-					case types.JsonLabel_Repeated:
-						br.WriteString(fmt.Sprintf(`%s"%s": [`, indent, field.JsonName))
-						br.WriteString(fmt.Sprintf("\n%s{\n", e.indent(layer+1)))
-
-						// 是否为 EntryMessage
-						if protoc.IsEntry(field) && len(nesteds.Fields) == 2 {
-							// nesteds.Fields[0]: key field
-							// nesteds.Fields[1]: value field
-							entryDemo1, entryDemo2 := new(types.MessageField), new(types.MessageField)
-							*entryDemo1, *entryDemo2 = *nesteds.Fields[1], *nesteds.Fields[1]
-							entryDemo1.JsonName, entryDemo2.JsonName = "key1", "key2"
-
-							var entryMessage = &types.Message{Fields: []*types.MessageField{entryDemo1, entryDemo2}}
-							br.WriteString(e.encodeMessage(entryMessage, layer+2))
-						} else {
-							br.WriteString(e.encodeMessage(nesteds, layer+2))
-						}
-
-						br.WriteString(fmt.Sprintf("\n%s}\n", e.indent(layer+1)))
-						br.WriteString(fmt.Sprintf(`%s]`, indent))
-					default:
-						br.WriteString(fmt.Sprintf(`%s"%s": {`, indent, field.JsonName))
-						br.WriteString("\n")
-
-						// 是否为 EntryMessage
-						if protoc.IsEntry(field) && len(nesteds.Fields) == 2 {
-							// nesteds.Fields[0]: key field
-							// nesteds.Fields[1]: value field
-							entryDemo1, entryDemo2 := new(types.MessageField), new(types.MessageField)
-							*entryDemo1, *entryDemo2 = *nesteds.Fields[1], *nesteds.Fields[1]
-							entryDemo1.JsonName, entryDemo2.JsonName = "key1", "key2"
-
-							var entryMessage = &types.Message{Fields: []*types.MessageField{entryDemo1, entryDemo2}}
-							br.WriteString(e.encodeMessage(entryMessage, layer+1))
-						} else {
-							br.WriteString(e.encodeMessage(nesteds, layer+1))
-						}
-
-						br.WriteString("\n")
-						br.WriteString(fmt.Sprintf(`%s}`, indent))
-					}
-				}
+// encodeMessage . excludes 为不需要的字段路径
+func (e *encoder) encodeMessage(mess *types.Message, layer int, excludes ...string) string {
+	indent := e.indent(layer)
+
+	var list = make([]string, 0, len(mess.Fields))
+	for _, field := range e.alternatives(mess.Fields) {
+		if types.Contains(excludes, field.ProtoName) {
+			continue
+		}
+		list = append(list, fmt.Sprintf(`%s"%s": %s`, indent, field.JsonName, e.encodeField(field, layer, types.SubPaths(excludes, field.ProtoName)...)))
+	}
+	return strings.Join(list, ",\n")
+}
+
+// alternatives 同一 oneof group 中只保留第一个字段
+func (e *encoder) alternatives(fields []*types.MessageField) []*types.MessageField {
+	var (
+		oneofs = make(map[string]struct{}, 0)
+		list   = make([]*types.MessageField, 0, len(fields))
+	)
+	for _, field := range fields {
+		if len(field.ProtoOneof) != 0 {
+			if _, found := oneofs[field.ProtoOneof]; found {
+				continue
 			}
-		default:
+			oneofs[field.ProtoOneof] = struct{}{}
+		}
+		list = append(list, field)
+	}
+	return list
+}
+
+// encodeField field value. excludes 为嵌套结构中不需要的字段路径
+func (e *encoder) encodeField(field *types.MessageField, layer int, excludes ...string) string {
+	indent := e.indent(layer)
+
+	// @example
+	if len(field.Metadata.Example) != 0 {
+		return field.Metadata.ExampleJson()
+	}
+
+	var br strings.Builder
+	switch field.JsonType {
+	case types.JsonType_Object:
+		switch field.ProtoType {
+		case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+			value := e.encodeEnum(field.ProtoFullName, field.Rules)
+
 			switch field.JsonLabel {
 			case types.JsonLabel_Repeated:
-				br.WriteString(fmt.Sprintf(`%s"%s": [`, indent, field.JsonName))
-				br.WriteString(fmt.Sprintf("\n%s%v\n", e.indent(layer+1), field.JsonDefaultValue))
-				br.WriteString(fmt.Sprintf(`%s]`, indent))
+				br.WriteString("[")
+				br.WriteString(fmt.Sprintf("\n%s%v\n", e.indent(layer+1), value))
+				br.WriteString(fmt.Sprintf("%s]", indent))
 			default:
-				br.WriteString(fmt.Sprintf(`%s"%s": %v`, indent, field.JsonName, field.JsonDefaultValue))
+				br.WriteString(fmt.Sprintf(`"%s"`, value))
+			}
+		case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+			if value, found := wellknowns[field.ProtoWellKnown]; found {
+				return e.encodeValue(field, value, layer)
+			}
+
+			// 预防同名结构体嵌套导致 goroutine 堆栈字节溢出
+			if nesteds := fmt.Sprintf("%s.%s", field.MessageName, field.ProtoName); e.nesteds[nesteds] == 2 {
+				return "null"
+			} else {
+				e.nesteds[nesteds]++
+			}
+
+			if nesteds, found := e.p.MessageDic[field.ProtoFullName]; found {
+				// 是否为 EntryMessage
+				if protoc.IsEntry(field) && len(nesteds.Fields) == 2 {
+					// nesteds.Fields[0]: key field
+					// nesteds.Fields[1]: value field
+					entryDemo1, entryDemo2 := new(types.MessageField), new(types.MessageField)
+					*entryDemo1, *entryDemo2 = *nesteds.Fields[1], *nesteds.Fields[1]
+					entryDemo1.JsonName, entryDemo2.JsonName = "key1", "key2"
+
+					nesteds = &types.Message{Fields: []*types.MessageField{entryDemo1, entryDemo2}}
+				}
+
+				switch {
+				// map<k, v> 为 object
+				case field.JsonLabel == types.JsonLabel_Repeated && !protoc.IsEntry(field):
+					br.WriteString("[")
+					br.WriteString(fmt.Sprintf("\n%s{\n", e.indent(layer+1)))
+					br.WriteString(e.encodeMessage(nesteds, layer+2))
+					br.WriteString(fmt.Sprintf("\n%s}\n", e.indent(layer+1)))
+					br.WriteString(fmt.Sprintf(`%s]`, indent))
+				default:
+					br.WriteString("{\n")
+					br.WriteString(e.encodeMessage(nesteds, layer+1, excludes...))
+					br.WriteString("\n")
+					br.WriteString(fmt.Sprintf(`%s}`, indent))
+				}
+			} else {
+				return "null"
 			}
 		}
-	ending:
-		if idx != len(mess.Fields)-1 {
-			br.WriteString(",\n")
-		}
+	default:
+		return e.encodeValue(field, e.defaultValue(field), layer)
 	}
 	return br.String()
 }
 
-// encodeEnum .
-func (e *encoder) encodeEnum(enumName string) string {
+// defaultValue 基础类型的示例值. 有校验规则时返回满足规则的值
+func (e *encoder) defaultValue(field *types.MessageField) interface{} {
+	var rules = field.Rules
+	if rules == nil || field.ProtoLaber == descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return field.JsonDefaultValue
+	}
+
+	switch field.JsonType {
+	case types.JsonType_Number:
+		switch {
+		case len(rules.Const) != 0:
+			return rules.Const
+		case len(rules.In) != 0:
+			return rules.In[0]
+		case len(rules.Minimum) != 0 && (rules.Minimum[0] != '-' && rules.Minimum != "0" || rules.Minimum == "0" && rules.ExclusiveMinimum):
+			// 最小值大于 0
+			if rules.ExclusiveMinimum {
+				return step(rules.Minimum, 1)
+			}
+			return rules.Minimum
+		case len(rules.Maximum) != 0 && (rules.Maximum[0] == '-' || rules.Maximum == "0" && rules.ExclusiveMaximum):
+			// 最大值小于 0
+			if rules.ExclusiveMaximum {
+				return step(rules.Maximum, -1)
+			}
+			return rules.Maximum
+		}
+	case types.JsonType_String:
+		if field.ProtoType != descriptorpb.FieldDescriptorProto_TYPE_STRING {
+			break
+		}
+
+		var value = "string"
+		switch {
+		case len(rules.Const) != 0:
+			return quote(rules.Const)
+		case len(rules.In) != 0:
+			return quote(rules.In[0])
+		case len(formats[rules.Format]) != 0:
+			return quote(formats[rules.Format])
+		case !strings.Contains(value, rules.Contains):
+			value += rules.Contains
+		}
+
+		// 长度不包括 prefix、suffix
+		var min, max = -1, -1
+		if rules.MinLen != nil {
+			min = int(*rules.MinLen) - utf8.RuneCountInString(rules.Prefix+rules.Suffix)
+		}
+		if rules.MaxLen != nil {
+			max = int(*rules.MaxLen) - utf8.RuneCountInString(rules.Prefix+rules.Suffix)
+		}
+		if length := utf8.RuneCountInString(value); min > length {
+			value += strings.Repeat("x", min-length)
+		}
+		if max >= 0 && utf8.RuneCountInString(value) > max {
+			value = string([]rune(value)[:max])
+		}
+		return quote(rules.Prefix + value + rules.Suffix)
+	}
+	return field.JsonDefaultValue
+}
+
+// step 数值加减 1
+func step(v string, delta int64) string {
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return strconv.FormatInt(i+delta, 10)
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return strconv.FormatFloat(f+float64(delta), 'g', -1, 64)
+	}
+	return v
+}
+
+// quote json string
+func quote(v string) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// encodeValue 基础类型的字段值
+func (e *encoder) encodeValue(field *types.MessageField, value interface{}, layer int) string {
+	switch field.JsonLabel {
+	case types.JsonLabel_Repeated:
+		return fmt.Sprintf("[\n%s%v\n%s]", e.indent(layer+1), value, e.indent(layer))
+	default:
+		return fmt.Sprintf(`%v`, value)
+	}
+}
+
+// encodeEnum . 有校验规则 const、in 时返回对应的枚举值
+func (e *encoder) encodeEnum(enumName string, rules *types.FieldRules) string {
 	if enum, found := e.p.EnumDic[enumName]; found && len(enum.Fields) != 0 {
-		return enum.Fields[0].Name
+		var value string
+		switch {
+		case rules == nil:
+		case len(rules.Const) != 0:
+			value = rules.Const
+		case len(rules.In) != 0:
+			value = rules.In[0]
+		}
 
+		for _, field := range enum.Fields {
+			if strconv.Itoa(int(field.Value)) == value {
+				return field.Name
+			}
+		}
+		return enum.Fields[0].Name
 	}
 	return ""
 }
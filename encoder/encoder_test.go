@@ -0,0 +1,69 @@
+package encoder
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types/typestest"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestEncodeBodyNestedExcludes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		excludes []string
+		want     map[string][]string
+	}{
+		{
+			name:     "whole body",
+			body:     "*",
+			excludes: []string{"user.user_id"},
+			want:     map[string][]string{"": {"user", "mask"}, "user": {"name"}},
+		},
+		{
+			name:     "whole body top level",
+			body:     "*",
+			excludes: []string{"mask"},
+			want:     map[string][]string{"": {"user"}, "user": {"user_id", "name"}},
+		},
+		{
+			name:     "body field",
+			body:     "user",
+			excludes: []string{"user.user_id"},
+			want:     map[string][]string{"": {"name"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := typestest.NewPackage(
+				typestest.NewMessage("UpdateRequest", typestest.MessageField("user", "User", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL), typestest.ScalarField("mask")),
+				typestest.NewMessage("User", typestest.ScalarField("user_id"), typestest.ScalarField("name")),
+			)
+			data := NewEncoder(p).EncodeBody("user.UpdateRequest", tt.body, tt.excludes...)
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(data), &body); err != nil {
+				t.Fatalf("invalid json %q: %v", data, err)
+			}
+			for path, keys := range tt.want {
+				var object = body
+				if len(path) != 0 {
+					object = nil
+					if err := json.Unmarshal(body[path], &object); err != nil {
+						t.Fatalf("%s: invalid json %q: %v", path, body[path], err)
+					}
+				}
+				if len(object) != len(keys) {
+					t.Errorf("%s: got %d keys in %s, want %v", path, len(object), data, keys)
+				}
+				for _, key := range keys {
+					if _, found := object[key]; !found {
+						t.Errorf("%s: key %q not found in %s", path, key, data)
+					}
+				}
+			}
+		})
+	}
+}
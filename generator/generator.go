@@ -2,5 +2,5 @@ package generator
 
 // Generator .
 type Generator interface {
-	Generate() []byte
+	Generate() ([]byte, error)
 }
@@ -7,9 +7,9 @@ import (
 	"github.com/charlesbases/protoc-gen-apidoc/conf"
 	"github.com/charlesbases/protoc-gen-apidoc/encoder"
 	"github.com/charlesbases/protoc-gen-apidoc/generator"
-	"github.com/charlesbases/protoc-gen-apidoc/logger"
 	"github.com/charlesbases/protoc-gen-apidoc/types"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 const defaultHost = "0.0.0.0"
@@ -55,7 +55,7 @@ func newPostman(p *types.Package) *Postman {
 			return h
 		}(),
 		Info: &Info{
-			ID:     uuid.New().String(),
+			ID:     stableID(p.Name),
 			Name:   conf.Get().Title,
 			Schema: "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
 		},
@@ -72,13 +72,8 @@ func NewGenerator(p *types.Package) generator.Generator {
 }
 
 // Generate .
-func (pt *Postman) Generate() []byte {
-	data, err := json.MarshalIndent(pt, "", "  ")
-	if err != nil {
-		logger.Fatal(err)
-	}
-
-	return data
+func (pt *Postman) Generate() ([]byte, error) {
+	return json.MarshalIndent(pt, "", "  ")
 }
 
 // parseServiceList .
@@ -91,52 +86,141 @@ func (pt *Postman) parseServiceList() {
 // parseService .
 func (pt *Postman) parseService(srv *types.Service) *Service {
 	var ptService = &Service{
-		Name: srv.Name,
-		Item: make([]*API, 0, len(srv.Methods)),
+		ID:          stableID(srv.Package, srv.Name),
+		Name:        srv.Name,
+		Description: strings.TrimSpace(srv.Description + "\n\n" + srv.Overview),
+		Item:        make([]*API, 0, len(srv.Methods)),
+	}
+	if len(srv.DisplayName) != 0 {
+		ptService.Name = srv.DisplayName
+	}
+	if len(srv.Metadata.Owner) != 0 {
+		ptService.Description = strings.TrimSpace(ptService.Description + "\n\nowner: " + srv.Metadata.Owner)
 	}
 
+	var header = pt.serviceHeader(srv)
 	for _, api := range srv.Methods {
-		ptService.Item = append(ptService.Item, pt.parseServiceAPI(api))
+		for _, binding := range api.Bindings {
+			ptAPI := pt.parseServiceAPI(api, binding)
+			ptAPI.ID = stableID(srv.Package, srv.Name, api.Name, binding.Method.String(), binding.Path)
+			ptAPI.Request.Header = header
+			ptService.Item = append(ptService.Item, ptAPI)
+		}
 	}
 
 	return ptService
 }
 
+// stableID 根据名称生成的 uuid, 保证每次生成的结果相同
+func stableID(names ...string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("apidoc:"+strings.Join(names, "/"))).String()
+}
+
+// serviceHeader 请求头. service 需要鉴权时添加鉴权请求头
+func (pt *Postman) serviceHeader(srv *types.Service) []*Header {
+	if len(srv.Authorization) == 0 {
+		return pt.header
+	}
+	for _, header := range pt.header {
+		if header.Key == srv.Authorization.String() {
+			return pt.header
+		}
+	}
+	return append(append(make([]*Header, 0, len(pt.header)+1), pt.header...), &Header{
+		Key:  srv.Authorization.String(),
+		Type: "default",
+	})
+}
+
 // parseServiceAPI .
-func (pt *Postman) parseServiceAPI(api *types.ServiceMethod) *API {
+func (pt *Postman) parseServiceAPI(api *types.ServiceMethod, binding *types.HttpBinding) *API {
+	path, interpolated := urlPath(binding.Template)
+
 	var ptAPI = &API{
-		Name: api.Path,
+		Name: binding.Path,
 		Request: &Request{
-			Method: api.Method,
+			Method: binding.Method,
 			Header: pt.header,
 			URL: &URL{
-				Raw:      conf.Get().Host + api.Path,
+				Raw:      conf.Get().Host + "/" + strings.Join(path, "/"),
 				Protocol: pt.host.Protocol,
 				Host:     pt.host.Host,
 				Port:     pt.host.Port,
-				Path:     strings.Split(strings.TrimPrefix(api.Path, "/"), "/"),
+				Path:     path,
 			},
 		},
 	}
 
+	switch {
+	case api.Streaming.IsClient() && api.Streaming.IsServer():
+		ptAPI.Request.Description = "bidi streaming: 请求、响应均为 newline-delimited JSON 流, 每行为一个消息. 请求消息结构: " + api.RequestName + ", 不提供 body 示例"
+	case api.Streaming.IsClient():
+		ptAPI.Request.Description = "client streaming: 请求为 newline-delimited JSON 流, 每行为一个消息. 请求消息结构: " + api.RequestName + ", 不提供 body 示例"
+	case api.Streaming.IsServer():
+		ptAPI.Request.Description = "server streaming: 响应为 newline-delimited JSON 流, 每行为一个消息"
+	}
+
+	// @summary
+	if len(api.Metadata.Summary) != 0 {
+		ptAPI.Request.Description = strings.TrimSpace(api.Metadata.Summary + "\n" + ptAPI.Request.Description)
+	}
+
+	// 已废弃的接口
+	if api.Deprecated {
+		ptAPI.Name = "[Deprecated] " + ptAPI.Name
+		ptAPI.Request.Description = strings.TrimSpace("deprecated: 接口已废弃, 请勿继续使用\n" + ptAPI.Request.Description)
+	}
+
+	// path 参数
+	var excludes = make([]string, 0, len(binding.PathParams))
+	for _, param := range binding.PathParams {
+		var variable = &Variable{Key: param.Name}
+		if param.Field != nil {
+			variable.Description = param.Field.Description
+		}
+		if len(param.Pattern) != 0 {
+			variable.Value = param.Pattern
+		}
+
+		if param.Name == interpolated {
+			pt.appendVariable(variable)
+		} else {
+			ptAPI.Request.URL.Variable = append(ptAPI.Request.URL.Variable, variable)
+		}
+		excludes = append(excludes, param.Name)
+	}
+
 	if mess, find := pt.p.MessageDic[api.RequestName]; find && len(mess.Fields) != 0 {
-		switch api.Method {
-		// Query
-		case types.MethodGet:
+		// Query: 未映射到 body 中的字段
+		if !binding.IsWholeBody() {
 			ptAPI.Request.URL.Query = make([]*Query, 0, len(mess.Fields))
 			for _, field := range mess.Fields {
+				if field.ProtoName == binding.Body || binding.IsPathParam(field.ProtoName) || !queryable(field) {
+					continue
+				}
+				// 嵌套字段为 path 参数时, 其所在的顶层字段不是 query 参数. 例: {user.user_id} => user
+				if len(types.SubPaths(excludes, field.ProtoName)) != 0 {
+					continue
+				}
+
 				ptAPI.Request.URL.Query = append(ptAPI.Request.URL.Query, &Query{
 					Key:         field.JsonName,
 					Description: field.Description,
+					// proto3 optional 字段可省略
+					Disabled: field.ProtoOptional,
 				})
 			}
-		// Body
-		default:
+		}
+
+		// Body. 流式请求的 body 为消息流, 单个消息的示例会产生误导, 只在描述中说明
+		if binding.HasBody() && !api.Streaming.IsClient() {
 			switch api.Consume {
 			case types.ContentTypeJson:
+				raw := encoder.NewEncoder(pt.p).EncodeBody(api.RequestName, binding.Body, excludes...)
+
 				ptAPI.Request.Body = &Body{
 					Mode: "raw",
-					Raw:  encoder.NewEncoder(pt.p).EncodeJson(api.RequestName),
+					Raw:  raw,
 					Options: BodyOptions{
 						Raw: struct {
 							Language string `json:"language"`
@@ -159,3 +243,49 @@ func (pt *Postman) parseServiceAPI(api *types.ServiceMethod) *API {
 
 	return ptAPI
 }
+
+// queryable 字段是否可以作为 query 参数, 与 swagger 一致.
+// message、map 以及 json 表示为 object 的 well-known type(Struct、Any 等)不能作为 query 参数
+func queryable(field *types.MessageField) bool {
+	switch {
+	case field.ProtoType != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		return true
+	case len(field.ProtoWellKnown) != 0:
+		return field.ProtoWellKnown.IsScalar()
+	default:
+		return false
+	}
+}
+
+// urlPath postman url path. path 参数转换为 ":param" 格式.
+// postman 中 ":param" 会占用整个路径段, 所以带有 verb 的 path 参数使用 "{{param}}" 变量, 并返回该参数名
+func urlPath(t *types.PathTemplate) (segments []string, interpolated string) {
+	segments = make([]string, 0, len(t.Segments))
+	for _, segment := range t.Segments {
+		if segment.IsVariable() {
+			segments = append(segments, ":"+segment.Variable)
+		} else {
+			segments = append(segments, segment.Literal)
+		}
+	}
+
+	// custom verb
+	if last := len(segments) - 1; len(t.Verb) != 0 && last >= 0 {
+		if variable := t.Segments[last]; variable.IsVariable() {
+			interpolated = variable.Variable
+			segments[last] = "{{" + variable.Variable + "}}"
+		}
+		segments[last] += ":" + t.Verb
+	}
+	return segments, interpolated
+}
+
+// appendVariable collection variable
+func (pt *Postman) appendVariable(variable *Variable) {
+	for _, item := range pt.Variable {
+		if item.Key == variable.Key {
+			return
+		}
+	}
+	pt.Variable = append(pt.Variable, variable)
+}
@@ -0,0 +1,162 @@
+package postman
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/conf"
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"github.com/charlesbases/protoc-gen-apidoc/types/typestest"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestMain(m *testing.M) {
+	if err := conf.Parse(""); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// wellKnownField .
+func wellKnownField(name string, wkt types.WellKnownType) *types.MessageField {
+	var field = typestest.MessageField(name, wkt.Name(), descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL)
+	field.ProtoFullName, field.ProtoWellKnown = string(wkt), wkt
+	return field
+}
+
+func TestQueryParams(t *testing.T) {
+	var entry = typestest.NewMessage("LabelsEntry", typestest.ScalarField("key"), typestest.ScalarField("value"))
+	entry.MapEntry = true
+
+	var labels = typestest.MessageField("labels", "LabelsEntry", descriptorpb.FieldDescriptorProto_LABEL_REPEATED)
+	labels.ProtoMapEntry = true
+
+	var p = typestest.NewPackage(
+		typestest.NewMessage("GetRequest",
+			typestest.MessageField("user", "User", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+			typestest.ScalarField("view"),
+			labels,
+			wellKnownField("meta", types.WellKnownStruct),
+			wellKnownField("read_mask", types.WellKnownFieldMask),
+			wellKnownField("since", types.WellKnownTimestamp),
+			typestest.MessageField("friends", "User", descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+		),
+		typestest.NewMessage("User", typestest.ScalarField("user_id"), typestest.ScalarField("name")),
+		entry,
+	)
+	p.AppendService(&types.Service{
+		Name:    "Users",
+		Package: typestest.Package,
+		Methods: []*types.ServiceMethod{{
+			Name:         "Get",
+			RequestName:  "user.GetRequest",
+			ResponseName: "user.User",
+			Bindings: []*types.HttpBinding{{
+				Path:       "/v1/users/{user.user_id}",
+				Method:     types.MethodGet,
+				Template:   &types.PathTemplate{Segments: []*types.PathSegment{{Literal: "v1"}, {Literal: "users"}, {Variable: "user.user_id"}}},
+				PathParams: []*types.PathParam{{Name: "user.user_id", Field: p.FieldByPath("user.GetRequest", "user.user_id")}},
+			}},
+		}},
+	})
+
+	data, err := NewGenerator(p).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pt struct {
+		Item []struct {
+			Item []struct {
+				Request struct {
+					URL struct {
+						Query []struct {
+							Key string `json:"key"`
+						} `json:"query"`
+					} `json:"url"`
+				} `json:"request"`
+			} `json:"item"`
+		} `json:"item"`
+	}
+	if err := json.Unmarshal(data, &pt); err != nil {
+		t.Fatal(err)
+	}
+	if len(pt.Item) != 1 || len(pt.Item[0].Item) != 1 {
+		t.Fatalf("got %s, want one request", data)
+	}
+
+	var keys = make([]string, 0)
+	for _, query := range pt.Item[0].Item[0].Request.URL.Query {
+		keys = append(keys, query.Key)
+	}
+	if want := []string{"view", "read_mask", "since"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got query %v, want %v", keys, want)
+	}
+}
+
+func TestClientStreamingBody(t *testing.T) {
+	var p = typestest.NewPackage(typestest.NewMessage("User", typestest.ScalarField("user_id"), typestest.ScalarField("name")))
+
+	var method = func(name string, streaming types.Streaming) *types.ServiceMethod {
+		return &types.ServiceMethod{
+			Name:         name,
+			Consume:      types.ContentTypeJson,
+			RequestName:  "user.User",
+			ResponseName: "user.User",
+			Streaming:    streaming,
+			Bindings: []*types.HttpBinding{{
+				Path:     "/v1/" + name,
+				Method:   types.MethodPost,
+				Template: &types.PathTemplate{Segments: []*types.PathSegment{{Literal: "v1"}, {Literal: name}}},
+				Body:     "*",
+			}},
+		}
+	}
+	p.AppendService(&types.Service{
+		Name:    "Users",
+		Package: typestest.Package,
+		Methods: []*types.ServiceMethod{method("unary", types.StreamingNone), method("upload", types.StreamingClient), method("chat", types.StreamingBidi)},
+	})
+
+	data, err := NewGenerator(p).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pt struct {
+		Item []struct {
+			Item []struct {
+				Name    string `json:"name"`
+				Request struct {
+					Description string          `json:"description"`
+					Body        json.RawMessage `json:"body"`
+				} `json:"request"`
+			} `json:"item"`
+		} `json:"item"`
+	}
+	if err := json.Unmarshal(data, &pt); err != nil {
+		t.Fatal(err)
+	}
+	if len(pt.Item) != 1 || len(pt.Item[0].Item) != 3 {
+		t.Fatalf("got %s, want three requests", data)
+	}
+
+	for _, api := range pt.Item[0].Item {
+		switch api.Name {
+		case "/v1/unary":
+			if len(api.Request.Body) == 0 {
+				t.Errorf("%s: body example not found", api.Name)
+			}
+		default:
+			if len(api.Request.Body) != 0 {
+				t.Errorf("%s: got body %s, want no example of streaming request", api.Name, api.Request.Body)
+			}
+			if !strings.Contains(api.Request.Description, "newline-delimited JSON 流") {
+				t.Errorf("%s: got description %q, want note of message stream", api.Name, api.Request.Description)
+			}
+		}
+	}
+}
@@ -8,25 +8,29 @@ type Postman struct {
 	host   *URL           `json:"-"`
 	header []*Header      `json:"-"`
 
-	Info *Info      `json:"info"`
-	Item []*Service `json:"item"`
+	Info     *Info       `json:"info"`
+	Item     []*Service  `json:"item"`
+	Variable []*Variable `json:"variable,omitempty"`
 }
 
 // Info .
 type Info struct {
-	ID     string `json:"-"`
+	ID     string `json:"_postman_id"`
 	Name   string `json:"name"`
 	Schema string `json:"schema"`
 }
 
 // Service .
 type Service struct {
-	Name string `json:"name"`
-	Item []*API `json:"item"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Item        []*API `json:"item"`
 }
 
 // API .
 type API struct {
+	ID       string    `json:"id"`
 	Name     string    `json:"name"`
 	Request  *Request  `json:"request,omitempty"`
 	Response *Response `json:"response,omitempty"`
@@ -34,10 +38,11 @@ type API struct {
 
 // Request .
 type Request struct {
-	Method types.Method `json:"method"`
-	Header []*Header    `json:"header,omitempty"`
-	Body   *Body        `json:"body,omitempty"`
-	URL    *URL         `json:"url"`
+	Method      types.Method `json:"method"`
+	Header      []*Header    `json:"header,omitempty"`
+	Body        *Body        `json:"body,omitempty"`
+	URL         *URL         `json:"url"`
+	Description string       `json:"description,omitempty"`
 }
 
 // Response .
@@ -82,10 +87,18 @@ type Query struct {
 
 // URL .
 type URL struct {
-	Raw      string   `json:"raw"`
-	Protocol string   `json:"protocol"`
-	Host     []string `json:"host"`
-	Port     string   `json:"port,omitempty"`
-	Path     []string `json:"path"`
-	Query    []*Query `json:"query,omitempty"`
+	Raw      string      `json:"raw"`
+	Protocol string      `json:"protocol"`
+	Host     []string    `json:"host"`
+	Port     string      `json:"port,omitempty"`
+	Path     []string    `json:"path"`
+	Query    []*Query    `json:"query,omitempty"`
+	Variable []*Variable `json:"variable,omitempty"`
+}
+
+// Variable path variable
+type Variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
 }
@@ -2,7 +2,8 @@ package swagger
 
 import (
 	"encoding/json"
-	"net/http"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/charlesbases/protoc-gen-apidoc/conf"
@@ -39,7 +40,7 @@ func NewGenerator(p *types.Package) generator.Generator {
 			}
 			return ""
 		}(),
-		BasePath: "",
+		BasePath: p.Prefix,
 		Schemes:  conf.Get().Schemes,
 		Paths:    make(map[string]map[string]*API, 0),
 		SecurityDefinitions: func() map[string]*Security {
@@ -65,46 +66,134 @@ func NewGenerator(p *types.Package) generator.Generator {
 }
 
 // Generate .
-func (s *Swagger) Generate() []byte {
-	data, err := json.MarshalIndent(s, "", "  ")
-	if err != nil {
-		logger.Fatal(err)
+func (s *Swagger) Generate() ([]byte, error) {
+	if err := s.errs.Err(); err != nil {
+		return nil, err
 	}
+	return json.MarshalIndent(s, "", "  ")
+}
 
-	return data
+// reflex return #/definitions/... well-known type 直接返回对应的 json 表示
+func (s *Swagger) reflex(fullName string) *Definition {
+	if wkt, found := types.WellKnown(fullName); found {
+		var def = *wellknowns[wkt]
+		return &def
+	}
+	return &Definition{Reflex: refprefix + s.defname(fullName)}
+}
+
+// defname definition name of message or enum
+func (s *Swagger) defname(fullName string) string {
+	if mess, found := s.p.MessageDic[fullName]; found {
+		return mess.Name
+	}
+	if enum, found := s.p.EnumDic[fullName]; found {
+		return enum.Name
+	}
+	return fullName
 }
 
-// reflex return #/definitions/...
-func (s *Swagger) reflex(defname string) *Definition {
-	return &Definition{Reflex: refprefix + defname}
+// definition return the Definition of message or enum
+func (s *Swagger) definition(fullName string) (*Definition, bool) {
+	def, found := s.Definitions[s.defname(fullName)]
+	return def, found
 }
 
 // parsePaths .
 func (s *Swagger) parseServices() {
 	for _, srv := range s.p.Services {
-		var tag = &Tag{
-			Name:        srv.Name,
-			Description: srv.Description,
+		var tags = []string{srv.Name}
+		switch {
+		// @tag
+		case len(srv.Metadata.Tags) != 0:
+			tags = srv.Metadata.Tags
+		case len(srv.DisplayName) != 0:
+			tags = []string{srv.DisplayName}
+		case len(s.p.Packages) > 1:
+			tags = []string{srv.Package + "." + srv.Name}
 		}
 
 		for _, m := range srv.Methods {
-			api := &API{
-				Tags:       []string{tag.Name},
-				Summary:    m.Description,
-				Consumes:   []types.ContentType{m.Consume},
-				Produces:   []types.ContentType{m.Produce},
-				Parameters: make([]*Parameter, 0),
-				Responses:  make(map[string]*Parameter),
+			// additional_bindings 共用同一个请求、响应结构
+			for _, b := range m.Bindings {
+				api := &API{
+					Tags:       tags,
+					Summary:    m.Description,
+					Consumes:   []types.ContentType{m.Consume},
+					Produces:   []types.ContentType{m.Produce},
+					Parameters: make([]*Parameter, 0),
+					Responses:  make(map[string]*Parameter),
+					Streaming:  m.Streaming,
+					Deprecated: m.Deprecated,
+					Since:      m.Metadata.Since,
+					Internal:   m.Metadata.Internal || srv.Metadata.Internal,
+				}
+
+				// @summary
+				if len(m.Metadata.Summary) != 0 {
+					api.Summary, api.Description = m.Metadata.Summary, m.Description
+				}
+				// @tag
+				if len(m.Metadata.Tags) != 0 {
+					api.Tags = m.Metadata.Tags
+				}
+
+				// 流式请求、响应为 newline-delimited JSON
+				if m.Streaming.IsClient() && b.HasBody() {
+					api.Consumes = []types.ContentType{types.ContentTypeNdjson}
+				}
+				if m.Streaming.IsServer() {
+					api.Produces = []types.ContentType{types.ContentTypeNdjson}
+				}
+
+				// 鉴权请求头
+				if len(srv.Authorization) != 0 {
+					s.appendSecurity(srv.Authorization)
+					api.Security = []map[string][]string{{srv.Authorization.String(): {}}}
+				}
+
+				api.parseResponses(s, m, b)
+				api.parseParameter(s, m, b)
+
+				s.push(m, s.uri(b.Template), operation(b.Method), api)
 			}
+		}
 
-			api.parseResponses(s, m)
-			api.parseParameter(s, m)
+		var description = srv.Description
+		if len(srv.Host) != 0 {
+			description += "\n\nHost: " + srv.Host
+		}
+		if len(srv.Overview) != 0 {
+			description += "\n\n" + srv.Overview
+		}
+		for _, name := range tags {
+			s.appendTag(&Tag{Name: name, Description: description, Owner: srv.Metadata.Owner})
+		}
+	}
+}
 
-			s.push(m.Path, m.Method.LowerCase(), api)
+// appendSecurity 添加请求头鉴权方式
+func (s *Swagger) appendSecurity(header types.Header) {
+	if s.SecurityDefinitions == nil {
+		s.SecurityDefinitions = make(map[string]*Security, 0)
+	}
+	if _, found := s.SecurityDefinitions[header.String()]; !found {
+		s.SecurityDefinitions[header.String()] = &Security{
+			Type: SecurityTypeApiKey,
+			Name: header,
+			In:   PositionHeader,
 		}
+	}
+}
 
-		s.Tags = append(s.Tags, tag)
+// appendTag 添加 tag. 多个 service 使用同一个 @tag 时只保留第一个
+func (s *Swagger) appendTag(tag *Tag) {
+	for _, item := range s.Tags {
+		if item.Name == tag.Name {
+			return
+		}
 	}
+	s.Tags = append(s.Tags, tag)
 }
 
 const refprefix = "#/definitions/"
@@ -118,7 +207,10 @@ func (s *Swagger) parseDefinitions() {
 
 	// parse messages
 	for _, mess := range s.p.Messages {
-		s.parseProtoMessage(mess)
+		// 被引用的 message 可能已经解析
+		if _, found := s.definition(mess.FullName); !found {
+			s.parseProtoMessage(mess)
+		}
 	}
 }
 
@@ -128,7 +220,7 @@ func (s *Swagger) parseProtoEnum() {
 		var def = &Definition{
 			Name: enum.Name,
 			Type: "string",
-			Enum: make([]string, 0, len(enum.Fields)),
+			Enum: make([]interface{}, 0, len(enum.Fields)),
 		}
 
 		// key list
@@ -137,14 +229,25 @@ func (s *Swagger) parseProtoEnum() {
 		}
 
 		// default
-		if len(def.Enum) != 0 {
-			def.Default = def.Enum[0]
+		if len(enum.Fields) != 0 {
+			def.Default = enum.Fields[0].Name
 		}
 
 		// desc TODO enum desc + enum.field desc
 		def.Description = enum.Description
+		def.Deprecated = enum.Deprecated
 
-		s.Definitions[enum.Name] = def
+		var deprecateds = make([]string, 0)
+		for _, field := range enum.Fields {
+			if field.Deprecated {
+				deprecateds = append(deprecateds, field.Name)
+			}
+		}
+		if len(deprecateds) != 0 {
+			def.Description = fmt.Sprintf("%s (deprecated: %s)", def.Description, strings.Join(deprecateds, ", "))
+		}
+
+		s.Definitions[s.defname(enum.FullName)] = def
 	}
 }
 
@@ -154,16 +257,31 @@ func (s *Swagger) parseProtoMessage(mess *types.Message) {
 		Name:        mess.Name,
 		Type:        "object",
 		Description: mess.Description,
+		Deprecated:  mess.Deprecated,
+		Example:     json.RawMessage(mess.Metadata.ExampleJson()),
+		Since:       mess.Metadata.Since,
 	}
 	fields := make(map[string]*Definition, len(mess.Fields))
+	def.Nesteds = fields
+
+	// 在解析字段之前注册, 防止引用自身或相互引用的 message 无限递归
+	s.Definitions[s.defname(mess.FullName)] = def
 
 	for _, mf := range mess.Fields {
-		fields[mf.ProtoName] = s.parseProtoMessageField(mf)
-	}
+		fields[mf.JsonName] = s.parseProtoMessageField(mf)
 
-	def.Nesteds = fields
+		// required
+		if mf.Required {
+			def.Required = append(def.Required, mf.JsonName)
+		}
 
-	s.Definitions[mess.Name] = def
+		if len(mf.ProtoOneof) != 0 {
+			if def.Oneofs == nil {
+				def.Oneofs = make(map[string][]string, len(mess.Oneofs))
+			}
+			def.Oneofs[mf.ProtoOneof] = append(def.Oneofs[mf.ProtoOneof], mf.JsonName)
+		}
+	}
 }
 
 // parseProtoMessageField .
@@ -175,17 +293,22 @@ func (s *Swagger) parseProtoMessageField(mf *types.MessageField) *Definition {
 	} else {
 		switch mf.ProtoType {
 		case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
-			field.Reflex = s.reflex(mf.ProtoTypeName).Reflex
+			field.Reflex = s.reflex(mf.ProtoFullName).Reflex
 		case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+			if def, found := wellknowns[mf.ProtoWellKnown]; found {
+				*field = *def
+				break
+			}
+
 			// 优先解析嵌套 message
-			if _, found := s.Definitions[mf.ProtoTypeName]; !found {
-				if mess, found := s.p.MessageDic[mf.ProtoTypeName]; found {
+			if _, found := s.definition(mf.ProtoFullName); !found {
+				if mess, found := s.p.MessageDic[mf.ProtoFullName]; found {
 					s.parseProtoMessage(mess)
 				}
 			}
 
 			if protoc.IsEntry(mf) {
-				if entry, found := s.Definitions[mf.ProtoTypeName]; found && len(entry.Nesteds) != 0 {
+				if entry, found := s.definition(mf.ProtoFullName); found && len(entry.Nesteds) != 0 {
 					// if key, k_found := entry.Nesteds["key"]; k_found {
 					//
 					// }
@@ -194,32 +317,238 @@ func (s *Swagger) parseProtoMessageField(mf *types.MessageField) *Definition {
 					}
 				}
 			} else {
-				field.Reflex = s.reflex(mf.ProtoTypeName).Reflex
+				field.Reflex = s.reflex(mf.ProtoFullName).Reflex
 			}
 		}
 	}
 
 	// 字段说明
 	field.Description = mf.Description
+	if len(mf.ProtoOneof) != 0 {
+		field.Description = fmt.Sprintf("%s (oneof %s)", mf.Description, mf.ProtoOneof)
+	}
+	field.Nullable = field.Nullable || mf.ProtoOptional
+	field.Deprecated = mf.Deprecated
+	field.Example = json.RawMessage(mf.Metadata.ExampleJson())
+	field.Since = mf.Metadata.Since
 
 	// proto laber
-	switch mf.ProtoLaber {
+	switch {
+	// map<k, v>
+	case protoc.IsEntry(mf):
+		field.Type = "object"
+		field.Constraint = constraint(mf)
+		return field
 	// repeated
-	case descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+	case mf.ProtoLaber == descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
 		return &Definition{
-			Type:  "array",
-			Items: field,
+			Type:       "array",
+			Items:      field,
+			Deprecated: mf.Deprecated,
+			Constraint: constraint(mf),
 		}
 	default:
+		field.Constraint = constraint(mf)
+		if len(field.Reflex) == 0 {
+			field.Enum = enum(mf, field.Type, field.Enum)
+		}
+		if len(field.Reflex) == 0 && field.Type == "string" {
+			field.Format = format(mf, field.Format)
+		}
 		return field
 	}
 }
 
+// constraint validation rules of field. $ref 的同级属性会被忽略, 所以 enum、message 类型不设置
+func constraint(mf *types.MessageField) Constraint {
+	var c Constraint
+
+	var rules = mf.Rules
+	if rules == nil {
+		return c
+	}
+
+	// repeated、map
+	switch {
+	case protoc.IsEntry(mf):
+		c.MinProperties, c.MaxProperties = rules.MinItems, rules.MaxItems
+		return c
+	case mf.ProtoLaber == descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+		c.MinItems, c.MaxItems, c.UniqueItems = rules.MinItems, rules.MaxItems, rules.UniqueItems
+		return c
+	}
+
+	switch mf.JsonType {
+	case types.JsonType_Number:
+		c.Minimum, c.ExclusiveMinimum = json.Number(rules.Minimum), rules.ExclusiveMinimum
+		c.Maximum, c.ExclusiveMaximum = json.Number(rules.Maximum), rules.ExclusiveMaximum
+	case types.JsonType_String:
+		c.MinLength, c.MaxLength, c.Pattern = rules.MinLen, rules.MaxLen, rules.Pattern
+	}
+	return c
+}
+
+// enum 字段的可选值. const 或 in. typ 为 integer、number 时输出为 json number
+func enum(mf *types.MessageField, typ string, def []interface{}) []interface{} {
+	var values []string
+	switch {
+	case mf.Rules == nil:
+		return def
+	case len(mf.Rules.Const) != 0:
+		values = []string{mf.Rules.Const}
+	case len(mf.Rules.In) != 0:
+		values = mf.Rules.In
+	default:
+		return def
+	}
+
+	var list = make([]interface{}, 0, len(values))
+	for _, v := range values {
+		switch typ {
+		case "integer", "number":
+			list = append(list, json.Number(v))
+		default:
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// format string 字段的格式. 例: email、uuid
+func format(mf *types.MessageField, def string) string {
+	if mf.Rules != nil && len(mf.Rules.Format) != 0 {
+		return mf.Rules.Format
+	}
+	return def
+}
+
+// uri swagger path, 不包括 basePath
+func (s *Swagger) uri(t *types.PathTemplate) string {
+	var path = uri(t)
+	switch {
+	case len(s.BasePath) == 0:
+		return path
+	case path == s.BasePath:
+		return "/"
+	case strings.HasPrefix(path, s.BasePath+"/"):
+		return strings.TrimPrefix(path, s.BasePath)
+	default:
+		return path
+	}
+}
+
+// uri swagger path. path 参数只保留参数名, 例: /v1/{name=projects/*}:publish => /v1/{name}:publish
+func uri(t *types.PathTemplate) string {
+	var br strings.Builder
+	for _, segment := range t.Segments {
+		br.WriteString("/")
+		if segment.IsVariable() {
+			br.WriteString("{" + segment.Variable + "}")
+		} else {
+			br.WriteString(segment.Literal)
+		}
+	}
+	if len(t.Verb) != 0 {
+		br.WriteString(":" + t.Verb)
+	}
+	// 根路径
+	if br.Len() == 0 {
+		return "/"
+	}
+	return br.String()
+}
+
+// pattern path 参数匹配规则转换为正则表达式. 例: projects/*/books/* => ^projects/[^/]+/books/[^/]+$
+func pattern(p string) string {
+	var list = strings.Split(p, "/")
+	for idx, item := range list {
+		switch item {
+		case "*":
+			list[idx] = "[^/]+"
+		case "**":
+			list[idx] = ".*"
+		default:
+			list[idx] = regexp.QuoteMeta(item)
+		}
+	}
+	return "^" + strings.Join(list, "/") + "$"
+}
+
+// operation path item 中的 operation 名称. swagger 2.0 不支持的自定义请求方式使用 "x-" 扩展
+func operation(m types.Method) string {
+	if m.IsCustom() {
+		return "x-" + m.LowerCase()
+	}
+	return m.LowerCase()
+}
+
+// field return the Definition of message field. fieldName 为 proto 字段名
+func (s *Swagger) field(messName string, fieldName string) *Definition {
+	if mess, found := s.definition(messName); found {
+		if field, found := mess.Nesteds[s.jsonName(messName, fieldName)]; found {
+			return field
+		}
+	}
+	return nil
+}
+
+// without 排除字段路径后的 Definition 副本. paths 为 proto 字段路径, 嵌套字段以 "." 连接, 例: user.user_id
+func (s *Swagger) without(messName string, paths ...string) *Definition {
+	def, found := s.definition(messName)
+	if !found {
+		return s.reflex(messName)
+	}
+
+	var names = make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !strings.Contains(path, ".") {
+			names = append(names, s.jsonName(messName, path))
+		}
+	}
+	var copied = def.without(names...)
+
+	// 嵌套字段所在的结构不再使用 $ref, 替换为排除该字段后的副本
+	if mess, found := s.p.MessageDic[messName]; found {
+		for _, field := range mess.Fields {
+			nested, found := copied.Nesteds[field.JsonName]
+			if subs := types.SubPaths(paths, field.ProtoName); len(subs) != 0 && found && len(nested.Reflex) != 0 {
+				schema := s.without(field.ProtoFullName, subs...)
+				schema.Description = nested.Description
+				copied.Nesteds[field.JsonName] = schema
+			}
+		}
+	}
+	return copied
+}
+
+// jsonName 字段在 Definition.Nesteds 中的名称. fieldName 为 proto 字段名, 嵌套字段路径原样返回
+func (s *Swagger) jsonName(messName string, fieldName string) string {
+	if strings.Contains(fieldName, ".") {
+		return fieldName
+	}
+	if field := s.p.FieldByPath(messName, fieldName); field != nil {
+		return field.JsonName
+	}
+	return fieldName
+}
+
+// fieldNames 结构中的字段在 Definition.Nesteds 中的名称, 按字段顺序
+func (s *Swagger) fieldNames(messName string) []string {
+	var names = make([]string, 0)
+	if mess, found := s.p.MessageDic[messName]; found {
+		for _, field := range mess.Fields {
+			names = append(names, field.JsonName)
+		}
+	}
+	return names
+}
+
 // push api
-func (s *Swagger) push(uri string, method string, api *API) {
+func (s *Swagger) push(m *types.ServiceMethod, uri string, method string, api *API) {
 	if apis, found := s.Paths[uri]; found {
 		if _, found := apis[method]; found {
-			logger.Fatalf("duplicate route. %s [%s]", uri, method)
+			s.errs.Append(types.Errorf(m.Position, "duplicate route. %s [%s]", uri, method))
+			return
 		}
 
 		apis[method] = api
@@ -232,41 +561,75 @@ func (s *Swagger) push(uri string, method string, api *API) {
 }
 
 // parameterPosition .
-func (api *API) parameterPosition(m *types.ServiceMethod) Position {
+func (api *API) parameterPosition(m *types.ServiceMethod, b *types.HttpBinding) Position {
 	if m.Consume == types.ContentTypeData {
 		return PositionFormData
 	}
 
-	switch m.Method {
-	case http.MethodGet:
-		return PositionQuery
-	default:
+	if b.HasBody() {
 		return PositionBody
 	}
+	return PositionQuery
 }
 
 // parseResponses .
-func (api *API) parseResponses(s *Swagger, m *types.ServiceMethod) {
+func (api *API) parseResponses(s *Swagger, m *types.ServiceMethod, b *types.HttpBinding) {
+	var schema = s.reflex(m.ResponseName)
+
+	// response_body 仅返回响应结构中的指定字段
+	if len(b.ResponseBody) != 0 {
+		if field := s.field(m.ResponseName, b.ResponseBody); field != nil {
+			schema = field
+		}
+	}
+
+	var description = "successful"
+
+	// 流式响应中每行为一个 {"result": ..., "error": ...} 消息
+	if m.Streaming.IsServer() {
+		description = "successful. (streaming responses)"
+		schema = &Definition{
+			Type:        "object",
+			Description: "Stream result of " + s.defname(m.ResponseName),
+			Nesteds: map[string]*Definition{
+				"result": schema,
+				"error":  {Type: "object"},
+			},
+		}
+	}
+
 	api.Responses = map[string]*Parameter{
 		"200": {
-			Description: "successful",
-			Schema:      s.reflex(m.ResponseName),
+			Description: description,
+			Schema:      schema,
 		},
 	}
 }
 
 // parseParameter .
-func (api *API) parseParameter(s *Swagger, m *types.ServiceMethod) {
+func (api *API) parseParameter(s *Swagger, m *types.ServiceMethod, b *types.HttpBinding) {
 	// api.parseParameterInHeader()
-	api.parseParameterInPath(m)
+	api.parseParameterInPath(s, b)
+
+	// path 参数不再出现在 body、query 中
+	var excludes = make([]string, 0, len(b.PathParams)+1)
+	for _, param := range b.PathParams {
+		// 嵌套字段为 path 参数时, 排除其所在的顶层字段. 例: {user.user_id} => user
+		excludes = append(excludes, s.jsonName(m.RequestName, strings.SplitN(param.Name, ".", 2)[0]))
+	}
 
-	switch api.parameterPosition(m) {
+	switch api.parameterPosition(m, b) {
 	case PositionBody:
-		api.parseParameterInBody(s, m)
+		api.parseParameterInBody(s, m, b)
+
+		// body 仅映射了请求结构中的指定字段时, 其余字段为 query 参数
+		if !b.IsWholeBody() {
+			api.parseParameterInQuery(s, m, append(excludes, s.jsonName(m.RequestName, b.Body))...)
+		}
 	case PositionQuery:
-		api.parseParameterInQuery(s, m)
+		api.parseParameterInQuery(s, m, excludes...)
 	case PositionFormData:
-		api.parseParamterInFormData(s, m)
+		api.parseParamterInFormData(s, m, excludes...)
 	}
 }
 
@@ -285,40 +648,104 @@ func (api *API) parseParameter(s *Swagger, m *types.ServiceMethod) {
 // }
 
 // parseParameter .
-func (api *API) parseParameterInPath(m *types.ServiceMethod) {
-	var uri = m.Path
-	for len(uri) > 2 {
-		l, r := strings.Index(uri, "{"), strings.Index(uri, "}")
-		if l > 0 && r > 0 && r > l {
-			api.Parameters = append(api.Parameters, &Parameter{
-				In:       PositionPath,
-				Name:     uri[l+1 : r],
-				Type:     "string",
-				Required: false,
-			})
-			uri = uri[r+1:]
-		} else {
-			return
+func (api *API) parseParameterInPath(s *Swagger, b *types.HttpBinding) {
+	for _, param := range b.PathParams {
+		var parameter = &Parameter{
+			In:       PositionPath,
+			Name:     param.Name,
+			Type:     "string",
+			Required: true,
 		}
+
+		if len(param.Pattern) != 0 {
+			parameter.Pattern = pattern(param.Pattern)
+		}
+
+		if field := param.Field; field != nil {
+			parameter.Description = field.Description
+
+			if def, found := prototypes[field.ProtoType]; found {
+				parameter.Type = def.Type
+				parameter.Format = format(field, def.Format)
+				parameter.Enum = enum(field, def.Type, nil)
+				if len(parameter.Pattern) == 0 {
+					parameter.Constraint = constraint(field)
+				}
+			} else if field.ProtoType == descriptorpb.FieldDescriptorProto_TYPE_ENUM {
+				if def, found := s.definition(field.ProtoFullName); found {
+					parameter.Enum = def.Enum
+					parameter.Default = def.Default
+				}
+			}
+		}
+
+		api.Parameters = append(api.Parameters, parameter)
 	}
 }
 
 // parseParameterInBody .
-func (api *API) parseParameterInBody(s *Swagger, m *types.ServiceMethod) {
+func (api *API) parseParameterInBody(s *Swagger, m *types.ServiceMethod, b *types.HttpBinding) {
+	// path 参数的字段路径. 例: user.user_id
+	var paths = make([]string, 0, len(b.PathParams))
+	for _, param := range b.PathParams {
+		paths = append(paths, param.Name)
+	}
+
+	var schema = s.reflex(m.RequestName)
+	switch {
+	case !b.IsWholeBody():
+		if schema = s.field(m.RequestName, b.Body); schema == nil {
+			return
+		}
+		// 排除 body 字段中的 path 参数. 例: body: "user", path: {user.user_id}
+		if field := s.p.FieldByPath(m.RequestName, b.Body); field != nil && len(types.SubPaths(paths, b.Body)) != 0 && len(schema.Reflex) != 0 {
+			var description = schema.Description
+			schema = s.without(field.ProtoFullName, types.SubPaths(paths, b.Body)...)
+			schema.Description = description
+		}
+	case len(paths) != 0:
+		// 排除 path 参数后的请求结构
+		schema = s.without(m.RequestName, paths...)
+	}
+
+	var description = m.Description
+	if m.Streaming.IsClient() {
+		description += " (streaming inputs)"
+	}
+
+	// body 映射的字段为必须, 或 body 中有必须的字段
+	var required bool
+	if b.IsWholeBody() {
+		if _, found := s.definition(m.RequestName); found {
+			required = len(s.without(m.RequestName, paths...).Required) != 0
+		}
+	} else if field := s.p.FieldByPath(m.RequestName, b.Body); field != nil {
+		required = field.Required
+	}
+
 	api.Parameters = append(api.Parameters, &Parameter{
 		In:          PositionBody,
 		Name:        m.Name,
-		Required:    false,
-		Description: m.Description,
-		Schema:      s.reflex(m.RequestName),
+		Required:    required,
+		Description: description,
+		Schema:      schema,
 	})
 }
 
 // parseParameter .
-func (api *API) parseParameterInQuery(s *Swagger, m *types.ServiceMethod) {
-	if mess, found := s.Definitions[m.RequestName]; found {
-		// message fields
-		for name, field := range mess.Nesteds {
+func (api *API) parseParameterInQuery(s *Swagger, m *types.ServiceMethod, excludes ...string) {
+	if mess, found := s.definition(m.RequestName); found {
+		// message fields. 按字段顺序, 保证每次生成的结果相同
+		for _, name := range s.fieldNames(m.RequestName) {
+			field, found := mess.Nesteds[name]
+			if !found || types.Contains(excludes, name) {
+				continue
+			}
+			if !field.isQueryable() {
+				logger.WarnAt(m.Position, "field %s of %s cannot be a %s parameter, skipped", name, m.RequestName, PositionQuery)
+				continue
+			}
+
 			switch field.Type {
 			case "array":
 				// repeated nesteds
@@ -329,7 +756,7 @@ func (api *API) parseParameterInQuery(s *Swagger, m *types.ServiceMethod) {
 							In:          PositionQuery,
 							Name:        name,
 							Type:        field.Type,
-							Required:    false,
+							Required:    types.Contains(mess.Required, name),
 							Description: field.Description,
 							Items: &Definition{
 								Type:    def.Type,
@@ -337,14 +764,17 @@ func (api *API) parseParameterInQuery(s *Swagger, m *types.ServiceMethod) {
 								Default: def.Default,
 							},
 						})
+					} else {
+						logger.WarnAt(m.Position, "field %s of %s cannot be a %s parameter, skipped", name, m.RequestName, PositionQuery)
 					}
 				} else {
 					api.Parameters = append(api.Parameters, &Parameter{
 						In:          PositionQuery,
 						Name:        name,
 						Type:        field.Type,
-						Required:    false,
+						Required:    types.Contains(mess.Required, name),
 						Description: field.Description,
+						Constraint:  field.Constraint,
 						Items: &Definition{
 							Type: field.Items.Type,
 						},
@@ -359,19 +789,24 @@ func (api *API) parseParameterInQuery(s *Swagger, m *types.ServiceMethod) {
 							In:          PositionQuery,
 							Name:        name,
 							Type:        def.Type,
-							Required:    false,
+							Required:    types.Contains(mess.Required, name),
 							Enum:        def.Enum,
 							Default:     def.Default,
 							Description: def.Description,
 						})
+					} else {
+						logger.WarnAt(m.Position, "field %s of %s cannot be a %s parameter, skipped", name, m.RequestName, PositionQuery)
 					}
 				} else {
 					api.Parameters = append(api.Parameters, &Parameter{
 						In:          PositionQuery,
 						Name:        name,
 						Type:        field.Type,
-						Required:    false,
+						Format:      field.Format,
+						Required:    types.Contains(mess.Required, name),
+						Enum:        field.Enum,
 						Description: field.Description,
+						Constraint:  field.Constraint,
 					})
 				}
 			}
@@ -380,13 +815,23 @@ func (api *API) parseParameterInQuery(s *Swagger, m *types.ServiceMethod) {
 }
 
 // parseParamterInFormData .
-func (api *API) parseParamterInFormData(s *Swagger, m *types.ServiceMethod) {
-	if mess, found := s.Definitions[m.RequestName]; found {
-		// message fields
-		for name, field := range mess.Nesteds {
+func (api *API) parseParamterInFormData(s *Swagger, m *types.ServiceMethod, excludes ...string) {
+	if mess, found := s.definition(m.RequestName); found {
+		// message fields. 按字段顺序, 保证每次生成的结果相同
+		for _, name := range s.fieldNames(m.RequestName) {
+			field, found := mess.Nesteds[name]
+			if !found || types.Contains(excludes, name) {
+				continue
+			}
+			if !field.isQueryable() {
+				logger.WarnAt(m.Position, "field %s of %s cannot be a %s parameter, skipped", name, m.RequestName, PositionFormData)
+				continue
+			}
+
 			switch field.Type {
 			case "array":
 				// multipart/form-data 参数不支持 array
+				logger.WarnAt(m.Position, "field %s of %s cannot be a %s parameter, skipped", name, m.RequestName, PositionFormData)
 			default:
 				// nesteds
 				if len(field.Reflex) != 0 {
@@ -396,11 +841,13 @@ func (api *API) parseParamterInFormData(s *Swagger, m *types.ServiceMethod) {
 							In:          PositionFormData,
 							Name:        name,
 							Type:        def.Type,
-							Required:    false,
+							Required:    types.Contains(mess.Required, name),
 							Enum:        def.Enum,
 							Default:     def.Default,
 							Description: def.Description,
 						})
+					} else {
+						logger.WarnAt(m.Position, "field %s of %s cannot be a %s parameter, skipped", name, m.RequestName, PositionFormData)
 					}
 				} else {
 					if field.Format == "bytes" {
@@ -408,7 +855,7 @@ func (api *API) parseParamterInFormData(s *Swagger, m *types.ServiceMethod) {
 							In:          PositionFormData,
 							Name:        name,
 							Type:        "file",
-							Required:    false,
+							Required:    types.Contains(mess.Required, name),
 							Description: field.Description,
 						})
 					} else {
@@ -416,8 +863,11 @@ func (api *API) parseParamterInFormData(s *Swagger, m *types.ServiceMethod) {
 							In:          PositionFormData,
 							Name:        name,
 							Type:        field.Type,
-							Required:    false,
+							Format:      field.Format,
+							Required:    types.Contains(mess.Required, name),
+							Enum:        field.Enum,
 							Description: field.Description,
+							Constraint:  field.Constraint,
 						})
 					}
 				}
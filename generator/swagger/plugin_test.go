@@ -0,0 +1,210 @@
+package swagger
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/conf"
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"github.com/charlesbases/protoc-gen-apidoc/types/typestest"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestMain(m *testing.M) {
+	if err := conf.Parse(""); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// generate .
+func generate(t *testing.T, p *types.Package) *Swagger {
+	t.Helper()
+
+	data, err := NewGenerator(p).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s = new(Swagger)
+	if err := json.Unmarshal(data, s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestRecursiveMessage(t *testing.T) {
+	s := generate(t, typestest.NewPackage(
+		typestest.NewMessage("User",
+			typestest.ScalarField("name"),
+			typestest.MessageField("parent", "User", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+			typestest.MessageField("children", "User", descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+		),
+	))
+
+	user, found := s.Definitions["User"]
+	if !found {
+		t.Fatal("definition User not found")
+	}
+	if ref := user.Nesteds["parent"].Reflex; ref != refprefix+"User" {
+		t.Errorf("parent: got $ref %q, want %q", ref, refprefix+"User")
+	}
+	if children := user.Nesteds["children"]; children.Type != "array" || children.Items.Reflex != refprefix+"User" {
+		t.Errorf("children: got %s of %q, want array of %q", children.Type, children.Items.Reflex, refprefix+"User")
+	}
+}
+
+func TestMutuallyRecursiveMessages(t *testing.T) {
+	s := generate(t, typestest.NewPackage(
+		typestest.NewMessage("Ping", typestest.MessageField("pong", "Pong", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL)),
+		typestest.NewMessage("Pong", typestest.MessageField("ping", "Ping", descriptorpb.FieldDescriptorProto_LABEL_REPEATED)),
+	))
+
+	for name, ref := range map[string]string{"Ping": "pong", "Pong": "ping"} {
+		def, found := s.Definitions[name]
+		if !found {
+			t.Fatalf("definition %s not found", name)
+		}
+		if len(def.Nesteds) != 1 || def.Nesteds[ref] == nil {
+			t.Errorf("%s: got properties %v, want %s", name, def.Nesteds, ref)
+		}
+	}
+	if ref := s.Definitions["Ping"].Nesteds["pong"].Reflex; ref != refprefix+"Pong" {
+		t.Errorf("Ping.pong: got $ref %q, want %q", ref, refprefix+"Pong")
+	}
+	if ref := s.Definitions["Pong"].Nesteds["ping"].Items.Reflex; ref != refprefix+"Ping" {
+		t.Errorf("Pong.ping: got $ref %q, want %q", ref, refprefix+"Ping")
+	}
+}
+
+func TestNestedRecursiveMessage(t *testing.T) {
+	s := generate(t, typestest.NewPackage(
+		typestest.NewMessage("Node",
+			typestest.ScalarField("name"),
+			typestest.MessageField("children", "Node.Child", descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+		),
+		typestest.NewMessage("Node.Child",
+			typestest.MessageField("parent", "Node", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+			typestest.MessageField("siblings", "Node.Child", descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+		),
+	))
+
+	child, found := s.Definitions["Node.Child"]
+	if !found {
+		t.Fatalf("definition Node.Child not found in %v", s.Definitions)
+	}
+	if ref := child.Nesteds["parent"].Reflex; ref != refprefix+"Node" {
+		t.Errorf("parent: got $ref %q, want %q", ref, refprefix+"Node")
+	}
+	if siblings := child.Nesteds["siblings"]; siblings.Items == nil || siblings.Items.Reflex != refprefix+"Node.Child" {
+		t.Errorf("siblings: got %+v, want array of %q", siblings, refprefix+"Node.Child")
+	}
+}
+
+func TestNumericEnumRules(t *testing.T) {
+	level := &types.MessageField{
+		ProtoName: "level",
+		ProtoType: descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		JsonName:  "level",
+		JsonType:  types.JsonType_Number,
+		Rules:     &types.FieldRules{In: []string{"1", "2", "3"}},
+	}
+	id := &types.MessageField{
+		ProtoName: "id",
+		ProtoType: descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		JsonName:  "id",
+		JsonType:  types.JsonType_Number,
+		Rules:     &types.FieldRules{Const: "10"},
+	}
+	name := typestest.ScalarField("name")
+	name.Rules = &types.FieldRules{In: []string{"a", "b"}}
+
+	data, err := NewGenerator(typestest.NewPackage(typestest.NewMessage("Request", level, id, name))).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s struct {
+		Definitions map[string]struct {
+			Properties map[string]struct {
+				Type string            `json:"type"`
+				Enum []json.RawMessage `json:"enum"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	for field, want := range map[string][]string{
+		"level": {`1`, `2`, `3`},
+		"id":    {`10`},
+		"name":  {`"a"`, `"b"`},
+	} {
+		property := s.Definitions["Request"].Properties[field]
+
+		var got = make([]string, 0, len(property.Enum))
+		for _, v := range property.Enum {
+			got = append(got, string(v))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s (%s): got enum %v, want %v", field, property.Type, got, want)
+		}
+	}
+}
+
+func TestNestedPathParamInBody(t *testing.T) {
+	var p = typestest.NewPackage(
+		typestest.NewMessage("UpdateRequest", typestest.MessageField("user", "User", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL), typestest.ScalarField("mask")),
+		typestest.NewMessage("User", typestest.ScalarField("user_id"), typestest.ScalarField("name")),
+	)
+
+	var template = &types.PathTemplate{Segments: []*types.PathSegment{
+		{Literal: "v1"}, {Literal: "users"}, {Variable: "user.user_id"},
+	}}
+	var binding = func(method types.Method, body string) *types.HttpBinding {
+		return &types.HttpBinding{
+			Path:       "/v1/users/{user.user_id}",
+			Method:     method,
+			Template:   template,
+			Body:       body,
+			PathParams: []*types.PathParam{{Name: "user.user_id", Field: p.FieldByPath("user.UpdateRequest", "user.user_id")}},
+		}
+	}
+	p.AppendService(&types.Service{
+		Name:    "Users",
+		Package: "user",
+		Methods: []*types.ServiceMethod{
+			{Name: "Update", RequestName: "user.UpdateRequest", ResponseName: "user.User", Bindings: []*types.HttpBinding{binding(types.MethodPut, "*")}},
+			{Name: "Patch", RequestName: "user.UpdateRequest", ResponseName: "user.User", Bindings: []*types.HttpBinding{binding(types.MethodPatch, "user")}},
+		},
+	})
+
+	s := generate(t, p)
+
+	var schemas = make(map[string]*Definition, 0)
+	for method, api := range s.Paths["/v1/users/{user.user_id}"] {
+		for _, param := range api.Parameters {
+			if param.In == PositionBody {
+				schemas[method] = param.Schema
+			}
+		}
+	}
+	if len(schemas) != 2 {
+		t.Fatalf("got %d body parameters, want 2", len(schemas))
+	}
+
+	for method, schema := range schemas {
+		var user = schema
+		if nested, found := schema.Nesteds["user"]; found {
+			user = nested
+		}
+		if _, found := user.Nesteds["user_id"]; found || user.Nesteds["name"] == nil {
+			t.Errorf("%s: got user properties %v, want name without user_id", method, user.Nesteds)
+		}
+	}
+	if user := s.Definitions["User"]; user.Nesteds["user_id"] == nil {
+		t.Error("definition User should still have user_id")
+	}
+}
@@ -1,6 +1,8 @@
 package swagger
 
 import (
+	"encoding/json"
+
 	"github.com/charlesbases/protoc-gen-apidoc/types"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
@@ -44,6 +46,33 @@ var prototypes = map[descriptorpb.FieldDescriptorProto_Type]*Definition{
 	},
 }
 
+// wellknowns google.protobuf well-known type 的 json 表示
+var wellknowns = map[types.WellKnownType]*Definition{
+	types.WellKnownTimestamp: {Type: "string", Format: "date-time"},
+	types.WellKnownDuration:  {Type: "string", Format: "duration"},
+	types.WellKnownFieldMask: {Type: "string", Format: "field-mask"},
+	types.WellKnownStruct:    {Type: "object", Entry: &Definition{}},
+	types.WellKnownValue:     {},
+	types.WellKnownListValue: {Type: "array", Items: &Definition{}},
+	types.WellKnownEmpty:     {Type: "object"},
+	types.WellKnownAny: {
+		Type:    "object",
+		Nesteds: map[string]*Definition{"@type": {Type: "string"}},
+		Entry:   &Definition{},
+	},
+
+	// wrappers
+	types.WellKnownBoolValue:   {Type: "boolean", Format: "boolean", Nullable: true},
+	types.WellKnownBytesValue:  {Type: "string", Format: "bytes", Nullable: true},
+	types.WellKnownDoubleValue: {Type: "number", Format: "double", Nullable: true},
+	types.WellKnownFloatValue:  {Type: "number", Format: "float", Nullable: true},
+	types.WellKnownInt32Value:  {Type: "integer", Format: "int32", Nullable: true},
+	types.WellKnownInt64Value:  {Type: "integer", Format: "int64", Nullable: true},
+	types.WellKnownStringValue: {Type: "string", Format: "string", Nullable: true},
+	types.WellKnownUInt32Value: {Type: "integer", Format: "uint32", Nullable: true},
+	types.WellKnownUInt64Value: {Type: "integer", Format: "uint64", Nullable: true},
+}
+
 type Position string
 
 const (
@@ -66,6 +95,8 @@ const (
 // Swagger .
 type Swagger struct {
 	p *types.Package `json:"-"`
+	// errs errors while parsing
+	errs types.Errors `json:"-"`
 
 	// Swagger version
 	Swagger string `json:"swagger,omitempty"`
@@ -103,6 +134,8 @@ type Tag struct {
 	Name string `json:"name,omitempty"`
 	// Description tag description
 	Description string `json:"description,omitempty"`
+	// Owner @owner
+	Owner string `json:"x-owner,omitempty"`
 }
 
 // Definition model
@@ -116,12 +149,26 @@ type Definition struct {
 
 	// Format data type
 	Format string `json:"format,omitempty"`
+	// Nullable proto3 optional 字段可省略
+	Nullable bool `json:"x-nullable,omitempty"`
+	// Deprecated option deprecated = true
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Example @example
+	Example json.RawMessage `json:"example,omitempty"`
+	// Since @since
+	Since string `json:"x-since,omitempty"`
 
-	// Enum enum keys
-	Enum []string `json:"enum,omitempty"`
+	// Enum enum keys. 校验规则 const、in 的数值为 json.Number
+	Enum []interface{} `json:"enum,omitempty"`
 	// Default enum default
 	Default string `json:"default,omitempty"`
 
+	// Constraint validation rules
+	Constraint
+
+	// Required required fields of object
+	Required []string `json:"required,omitempty"`
+
 	// Reflex others Definition point
 	Reflex string `json:"$ref,omitempty"`
 
@@ -133,6 +180,68 @@ type Definition struct {
 
 	// Nesteds nested
 	Nesteds map[string]*Definition `json:"properties,omitempty"`
+
+	// Oneofs oneof groups. map[oneof][]field, 同一 group 中的字段最多只能设置一个
+	Oneofs map[string][]string `json:"x-oneof,omitempty"`
+}
+
+// without return a copy of Definition without the nested fields
+func (def *Definition) without(names ...string) *Definition {
+	var copied = *def
+	copied.Nesteds = make(map[string]*Definition, len(def.Nesteds))
+	for name, nested := range def.Nesteds {
+		if !types.Contains(names, name) {
+			copied.Nesteds[name] = nested
+		}
+	}
+	copied.Required = nil
+	for _, name := range def.Required {
+		if !types.Contains(names, name) {
+			copied.Required = append(copied.Required, name)
+		}
+	}
+	return &copied
+}
+
+// isQueryable 是否可以作为 query、formData 参数. object 类型(map、Struct 等)不能作为 query 参数
+func (def *Definition) isQueryable() bool {
+	switch {
+	case len(def.Reflex) != 0:
+		return true
+	case def.Type == "array":
+		return def.Items != nil && def.Items.isQueryable()
+	default:
+		return len(def.Type) != 0 && def.Type != "object"
+	}
+}
+
+// Constraint swagger validation keywords. 由 validate.rules、buf.validate.field 转换
+type Constraint struct {
+	// Minimum 最小值. ExclusiveMinimum 为 true 时不包含最小值
+	Minimum          json.Number `json:"minimum,omitempty"`
+	ExclusiveMinimum bool        `json:"exclusiveMinimum,omitempty"`
+	// Maximum 最大值. ExclusiveMaximum 为 true 时不包含最大值
+	Maximum          json.Number `json:"maximum,omitempty"`
+	ExclusiveMaximum bool        `json:"exclusiveMaximum,omitempty"`
+
+	// MinLength string 最小长度
+	MinLength *uint64 `json:"minLength,omitempty"`
+	// MaxLength string 最大长度
+	MaxLength *uint64 `json:"maxLength,omitempty"`
+	// Pattern regular expression
+	Pattern string `json:"pattern,omitempty"`
+
+	// MinItems array 最少元素个数
+	MinItems *uint64 `json:"minItems,omitempty"`
+	// MaxItems array 最多元素个数
+	MaxItems *uint64 `json:"maxItems,omitempty"`
+	// UniqueItems array 元素不能重复
+	UniqueItems bool `json:"uniqueItems,omitempty"`
+
+	// MinProperties map 最少元素个数
+	MinProperties *uint64 `json:"minProperties,omitempty"`
+	// MaxProperties map 最多元素个数
+	MaxProperties *uint64 `json:"maxProperties,omitempty"`
 }
 
 // Security .
@@ -160,6 +269,16 @@ type API struct {
 	Parameters []*Parameter `json:"parameters,omitempty"`
 	// Responses response
 	Responses map[string]*Parameter `json:"responses,omitempty"`
+	// Streaming rpc 流模式. client、server or bidi
+	Streaming types.Streaming `json:"x-streaming,omitempty"`
+	// Deprecated option deprecated = true
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Since @since
+	Since string `json:"x-since,omitempty"`
+	// Internal @internal
+	Internal bool `json:"x-internal,omitempty"`
+	// Security 鉴权方式. map[SecurityDefinitions.name][]scope
+	Security []map[string][]string `json:"security,omitempty"`
 }
 
 // Parameter .
@@ -167,11 +286,14 @@ type Parameter struct {
 	In       Position `json:"in,omitempty"`
 	Name     string   `json:"name,omitempty"`
 	Type     string   `json:"type,omitempty"`
+	Format   string   `json:"format,omitempty"`
 	Required bool     `json:"required,omitempty"`
-	// Enum enum keys
-	Enum []string `json:"enum,omitempty"`
+	// Enum enum keys. 校验规则 const、in 的数值为 json.Number
+	Enum []interface{} `json:"enum,omitempty"`
 	// Default default value
 	Default string `json:"default,omitempty"`
+	// Constraint validation rules. path 参数的匹配规则为 Pattern
+	Constraint
 	// Description description
 	Description string `json:"description,omitempty"`
 	// Schema Definition path
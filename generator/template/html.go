@@ -95,6 +95,16 @@ const HTML Template = `{{$packagename := .Name -}}
         height: 38px;
         padding-left: 5px;
       }
+      .badge {
+        background-color: #3DA0DB;
+        color: #FFFFFF;
+        border-radius: 3px;
+        padding: 2px 6px;
+        font-size: 60%;
+      }
+      .deprecated {
+        background-color: #F4606C;
+      }
       #back_top div {
         position: absolute;
         margin: auto;
@@ -157,11 +167,19 @@ const HTML Template = `{{$packagename := .Name -}}
     </ul>
     <h1 class="title"><a id="srv">服务</a></h1>
     <ul>
-    {{range $serviceindex, $service := .Services -}}
-      <li>{{$service.Name}}{{dynamic $service.Name}}[{{$service.Description}}]
+    {{range $groupindex, $group := .ServiceGroups -}}
+      <li>package {{$group.Package}}
         <ul>
-        {{range $apiindex, $method := $service.Methods -}}
-        <li><a href="#{{$service.Name}}.{{$method.Name}}">[{{$method.Method}}] {{$method.Path}}</a>{{dynamic $method.Path}}[{{$method.Description}}]</li>
+        {{range $serviceindex, $service := $group.Services -}}
+          <li>{{$service.Name}}{{if $service.DisplayName}} ({{$service.DisplayName}}){{end}}{{dynamic $service.Name}}[{{firstline $service.Description}}]
+            <ul>
+            {{range $apiindex, $method := $service.Methods -}}
+            {{range $bindingindex, $binding := $method.Bindings -}}
+            <li><a href="#{{$service.Package}}.{{$service.Name}}.{{$method.Name}}">[{{$binding.Method}}] {{strike $binding.Path $method.Deprecated}}</a>{{dynamic $binding.Path}}[{{firstline $method.Description}}]</li>
+            {{end}}
+            {{end}}
+            </ul>
+          </li>
         {{end}}
         </ul>
       </li>
@@ -169,37 +187,114 @@ const HTML Template = `{{$packagename := .Name -}}
     </ul>
     <HR>
     <h1 class="title">接口</h1>
-    {{range $serviceindex, $service := .Services -}}
+    {{range $groupindex, $group := .ServiceGroups -}}
+    <h2 class="service">package {{$group.Package}}</h2>
+    {{range $serviceindex, $service := $group.Services -}}
+    {{if or $service.Overview $service.DisplayName $service.Host $service.Prefix $service.Metadata.Owner $service.Authorization -}}
+    <h3>{{$service.Name}} 概述</h3>
+    {{if or $service.DisplayName $service.Host $service.Prefix $service.Metadata.Owner $service.Authorization -}}
+    <div class="codeblock">
+    {{if $service.DisplayName -}}
+    名称: {{$service.DisplayName}}</br>
+    {{end -}}
+    {{if $service.Host -}}
+    地址: {{$service.Host}}</br>
+    {{end -}}
+    {{if $service.Prefix -}}
+    前缀: {{$service.Prefix}}</br>
+    {{end -}}
+    {{if $service.Metadata.Owner -}}
+    负责人: {{$service.Metadata.Owner}}</br>
+    {{end -}}
+    {{if $service.Authorization -}}
+    鉴权: 请求头 {{$service.Authorization}}</br>
+    {{end -}}
+    </div>
+    {{end -}}
+    {{if $service.Overview -}}
+    <pre><div class="codeblock">{{$service.Overview}}</div></pre>
+    {{end -}}
+    {{end -}}
     {{range $apiindex, $method := $service.Methods -}}
-    <h2 class="api"><a id="{{$service.Name}}.{{$method.Name}}">[{{$method.Method}}] {{$method.Path}}</a></h2>
+    {{$binding := index $method.Bindings 0 -}}
+    <h2 class="api"><a id="{{$service.Package}}.{{$service.Name}}.{{$method.Name}}">[{{$binding.Method}}] {{strike $binding.Path $method.Deprecated}}</a>{{if $method.Streaming}} <span class="badge">{{$method.Streaming}} streaming</span>{{end}}{{if $method.Deprecated}} <span class="badge deprecated">deprecated</span>{{end}}{{if or $method.Metadata.Internal $service.Metadata.Internal}} <span class="badge">internal</span>{{end}}</h2>
     <div class="codeblock">
-    服务: {{$service.Name}}</br>
-    描述: {{$method.Description}}</br>
+    服务: {{$service.Package}}.{{$service.Name}}</br>
+    {{if $service.Authorization -}}
+    鉴权: 请求头 {{$service.Authorization}}</br>
+    {{end -}}
+    {{range $bindingindex, $binding := $method.Bindings -}}
+    路由: [{{$binding.Method}}] {{$binding.Path}}{{if and $binding.HasBody (not $binding.IsWholeBody)}}    body: {{$binding.Body}}{{end}}</br>
+    {{end -}}
+    描述: {{multiline $method.Description}}</br>
+    {{if $method.Metadata.Summary -}}
+    简介: {{$method.Metadata.Summary}}</br>
+    {{end -}}
+    {{if $method.Metadata.Tags -}}
+    分组: {{join $method.Metadata.Tags ", "}}</br>
+    {{end -}}
+    {{if $method.Metadata.Since -}}
+    版本: since {{$method.Metadata.Since}}</br>
+    {{end -}}
+    {{if $method.Streaming -}}
+    流: {{$method.Streaming}} streaming, {{if $method.Streaming.IsClient}}请求{{end}}{{if and $method.Streaming.IsClient $method.Streaming.IsServer}}、{{end}}{{if $method.Streaming.IsServer}}响应{{end}}为 newline-delimited JSON 流, 每行为一个消息</br>
+    {{end -}}
     </font></div>
+    {{$params := pathParams $method -}}
+    {{if $params -}}
+    <h3>路径参数</h3>
+    <table class="pure-table">
+      <thead>
+        <tr>
+          <td>字段</td>
+          <td>类型</td>
+          <td>标签</td>
+          <td>约束</td>
+          <td>描述</td>
+        </tr>
+      </thead>
+      <tbody>
+        {{$index := 1}}{{range $paramindex, $param := $params -}}
+        <tr {{if polling $index}}class="pure-table-odd"{{end}}{{$index = increasing $index}}>
+          <td>{{$param.Name}}</td>
+          <td>{{if $param.Field}}<a href="#{{$param.Field.ProtoFullName}}">{{jsonType $param.Field}}</a>{{else}}String{{end}}</td>
+          <td>必须</td>
+          <td>{{if $param.Field}}{{constraints $param.Field}}{{end}}</td>
+          <td>{{if $param.Field}}{{multiline $param.Field.Description}}{{end}}</td>
+        </tr>
+        {{end}}
+      </tbody>
+    </table>
+    {{end -}}
     <h3>请求</h3>
-    {{$request := getMessage $method.RequestName -}}
     <table class="pure-table">
       <thead>
         <tr>
           <td>字段</td>
           <td>类型</td>
           <td>标签</td>
+          <td>约束</td>
           <td>描述</td>
         </tr>
       </thead>
       <tbody>
-        {{$index := 1}}{{range $fieldindex, $field := $request.Fields -}}
+        {{$index := 1}}{{range $fieldindex, $field := requestFields $method -}}
         <tr {{if polling $index}}class="pure-table-odd"{{end}}{{$index = increasing $index}}>
-          <td>{{$field.JsonName}}</td>
-          <td><a href="#{{$field.ProtoTypeName}}">{{jsonType $field}}</a></td>
-          <td>{{$field.JsonLabel}}</td>
-          <td>{{$field.Description}}</td>
+          <td>{{strike $field.JsonName $field.Deprecated}}</td>
+          <td><a href="#{{$field.ProtoFullName}}">{{jsonType $field}}</a></td>
+          <td>{{label $field}}</td>
+          <td>{{constraints $field}}</td>
+          <td>{{multiline $field.Description}}</td>
         </tr>
         {{end}}
       <tbody>
     </table>
-    <h4>示例</h4>
-    <pre><div class="codeblock">{{jsonMarshal $request.Name}}</div></pre>
+    {{range $bindingindex, $binding := $method.Bindings -}}
+    {{if $binding.HasBody -}}
+    <h4>示例{{if gt (len $method.Bindings) 1}} [{{$binding.Method}}] {{$binding.Path}}{{end}}</h4>
+    <pre><div class="codeblock">{{requestJson $method $binding}}</div></pre>
+    {{end -}}
+    {{end -}}
     <h3>响应</h3>
     {{$response := getMessage $method.ResponseName -}}
     <table class="pure-table">
@@ -208,22 +303,25 @@ const HTML Template = `{{$packagename := .Name -}}
           <td>字段</td>
           <td>类型</td>
           <td>标签</td>
+          <td>约束</td>
           <td>描述</td>
         </tr>
       </thead>
       <tbody>
       {{$index := 1}}{{range $fieldindex, $field := $response.Fields -}}
         <tr {{if polling $index}}class="pure-table-odd"{{end}}{{$index = increasing $index}}>
-          <td>{{$field.JsonName}}</td>
-          <td><a href="#{{$field.ProtoTypeName}}">{{jsonType $field}}</a></td>
-          <td>{{$field.JsonLabel}}</td>
-          <td>{{$field.Description}}</td>
+          <td>{{strike $field.JsonName $field.Deprecated}}</td>
+          <td><a href="#{{$field.ProtoFullName}}">{{jsonType $field}}</a></td>
+          <td>{{label $field}}</td>
+          <td>{{constraints $field}}</td>
+          <td>{{multiline $field.Description}}</td>
         </tr>
       {{end}}
       <tbody>
     </table>
     <h4>示例</h4>
-    <pre><div class="codeblock">{{jsonMarshal $response.Name}}</div></pre>
+    <pre><div class="codeblock">{{jsonMarshal $method.ResponseName}}</div></pre>
+    {{end}}
     {{end}}
     {{end}}
 
@@ -238,8 +336,8 @@ const HTML Template = `{{$packagename := .Name -}}
       <tbody>
         {{$index := 1}}{{range $messageindex, $message := .Messages -}}
         <tr {{if polling $index}}class="pure-table-odd"{{end}}{{$index = increasing $index}}>
-          <td><a href="#{{$message.Name}}">{{$message.Name}}</a></td>
-          <td>{{$message.Description}}</td>
+          <td><a href="#{{$message.FullName}}">{{strike $message.Name $message.Deprecated}}</a></td>
+          <td>{{multiline $message.Description}}</td>
         </tr>
         {{end}}
       </tbody>
@@ -248,24 +346,32 @@ const HTML Template = `{{$packagename := .Name -}}
     <!-- 结构列表 -->
     {{range $messageindex, $message := .Messages -}}
     <ul>
-      <li><h3><a id="{{$message.Name}}">{{$message.Name}}</a></h3></li>
-      <p><font color="#696969">说明: {{$message.Description}}</font></p>
+      <li><h3><a id="{{$message.FullName}}">{{strike $message.Name $message.Deprecated}}</a></h3></li>
+      <p><font color="#696969">说明: {{multiline $message.Description}}</font></p>
+      {{if $message.Metadata.Since -}}
+      <p><font color="#696969">版本: since {{$message.Metadata.Since}}</font></p>
+      {{end -}}
+      {{range $oneofindex, $oneof := $message.Oneofs -}}
+      <p><font color="#696969">oneof {{$oneof.Name}}: {{join $oneof.Fields " | "}}    {{multiline $oneof.Description}}</font></p>
+      {{end -}}
       <table class="pure-table">
         <thead>
           <tr>
             <td>字段</td>
             <td>类型</td>
             <td>标签</td>
+            <td>约束</td>
             <td>描述</td>
           </tr>
         </thead>
         <tbody>
           {{$index := 1}}{{range $fieldindex, $field := $message.Fields -}}
           <tr {{if polling $index}}class="pure-table-odd"{{end}}{{$index = increasing $index}}>
-            <td>{{$field.JsonName}}</td>
-            <td><a href="#{{$field.ProtoTypeName}}">{{jsonType $field}}</a></td>
-            <td>{{$field.JsonLabel}}</td>
-            <td>{{$field.Description}}</td>
+            <td>{{strike $field.JsonName $field.Deprecated}}</td>
+            <td><a href="#{{$field.ProtoFullName}}">{{jsonType $field}}</a></td>
+            <td>{{label $field}}</td>
+            <td>{{constraints $field}}</td>
+            <td>{{multiline $field.Description}}</td>
           </tr>
           {{end}}
         </tbody>
@@ -276,7 +382,7 @@ const HTML Template = `{{$packagename := .Name -}}
     <h1 class="title"><a id="enu">枚举</a></h1>
     {{range $enumindex, $enum := .Enums -}}
     <ul>
-      <li><h4><a id="{{$enum.Name}}">{{$enum.Name}}</a></h4></li>
+      <li><h4><a id="{{$enum.FullName}}">{{strike $enum.Name $enum.Deprecated}}</a></h4></li>
       <table class="pure-table">
         <thead>
           <tr>
@@ -288,9 +394,9 @@ const HTML Template = `{{$packagename := .Name -}}
         <tbody>
           {{$index := 1}}{{range $fieldindex, $field := $enum.Fields -}}
           <tr {{if polling $index}}class="pure-table-odd"{{end}}{{$index = increasing $index}}>
-            <td>{{$field.Name}}</td>
+            <td>{{strike $field.Name $field.Deprecated}}</td>
             <td>{{$field.Value}}</td>
-            <td>{{$enum.Description}}:  {{$field.Description}}</td>
+            <td>{{multiline $enum.Description}}:  {{multiline $field.Description}}</td>
           </tr>
           {{end}}
         </tbody>
@@ -12,67 +12,137 @@ Package {{$packagename}}
 
 ## 服务 <a name="srv"> </a>
 
-{{range $serviceindex, $service := .Services -}}
-+ ###### {{$service.Name}}  [{{$service.Description}}]
-  {{range $apiindex, $method := $service.Methods -}}
-  + [{{$method.Path}}](#{{$service.Name}}.{{$method.Name}}){{dynamic $method.Path}}[{{$method.Description}}]
-  {{end}}
+{{range $groupindex, $group := .ServiceGroups -}}
++ ##### package {{$group.Package}}
+{{- range $serviceindex, $service := $group.Services}}
+  + ###### {{$service.Name}}{{if $service.DisplayName}} ({{$service.DisplayName}}){{end}}  [{{firstline $service.Description}}]
+    {{range $apiindex, $method := $service.Methods -}}
+    {{range $bindingindex, $binding := $method.Bindings -}}
+    + [[{{$binding.Method}}] {{strike $binding.Path $method.Deprecated}}](#{{$service.Package}}.{{$service.Name}}.{{$method.Name}}){{dynamic $binding.Path}}[{{firstline $method.Description}}]
+    {{end}}
+    {{- end}}
+{{- end}}
 {{end}}
 ---
 
 ## 接口
-{{range $serviceindex, $service := .Services -}}
+{{range $groupindex, $group := .ServiceGroups -}}
+### package {{$group.Package}}
+{{range $serviceindex, $service := $group.Services -}}
+{{if or $service.Overview $service.DisplayName $service.Host $service.Prefix $service.Metadata.Owner $service.Authorization -}}
+#### {{$service.Name}} 概述
+{{codeblock}}
+{{if $service.DisplayName -}}
+名称: {{$service.DisplayName}}
+{{end -}}
+{{if $service.Host -}}
+地址: {{$service.Host}}
+{{end -}}
+{{if $service.Prefix -}}
+前缀: {{$service.Prefix}}
+{{end -}}
+{{if $service.Metadata.Owner -}}
+负责人: {{$service.Metadata.Owner}}
+{{end -}}
+{{if $service.Authorization -}}
+鉴权: 请求头 {{$service.Authorization}}
+{{end -}}
+{{if $service.Overview -}}
+{{$service.Overview}}
+{{end -}}
+{{codeblock}}
+{{end -}}
 {{range $apiindex, $method := $service.Methods -}}
-#### {{$method.Path}} <a name="{{$service.Name}}.{{$method.Name}}"> </a> [服务](#srv) [结构](#msg) [枚举](#enu)
+{{$binding := index $method.Bindings 0 -}}
+#### [{{$binding.Method}}] {{strike $binding.Path $method.Deprecated}}{{if $method.Streaming}} **[{{$method.Streaming}} streaming]**{{end}}{{if $method.Deprecated}} **[deprecated]**{{end}}{{if or $method.Metadata.Internal $service.Metadata.Internal}} **[internal]**{{end}} <a name="{{$service.Package}}.{{$service.Name}}.{{$method.Name}}"> </a> [服务](#srv) [结构](#msg) [枚举](#enu)
 {{codeblock}}
+服务: {{$service.Package}}.{{$service.Name}}
+{{if $service.Authorization -}}
+鉴权: 请求头 {{$service.Authorization}}
+{{end -}}
+{{range $bindingindex, $binding := $method.Bindings -}}
+路由: [{{$binding.Method}}] {{$binding.Path}}{{if and $binding.HasBody (not $binding.IsWholeBody)}}    body: {{$binding.Body}}{{end}}
+{{end -}}
 描述: {{$method.Description}}
+{{- if $method.Metadata.Summary}}
+简介: {{$method.Metadata.Summary}}
+{{- end}}
+{{- if $method.Metadata.Tags}}
+分组: {{join $method.Metadata.Tags ", "}}
+{{- end}}
+{{- if $method.Metadata.Since}}
+版本: since {{$method.Metadata.Since}}
+{{- end}}
+{{- if $method.Streaming}}
+流: {{$method.Streaming}} streaming, {{if $method.Streaming.IsClient}}请求{{end}}{{if and $method.Streaming.IsClient $method.Streaming.IsServer}}、{{end}}{{if $method.Streaming.IsServer}}响应{{end}}为 newline-delimited JSON 流, 每行为一个消息
+{{- end}}
 {{codeblock}}
+{{$params := pathParams $method -}}
+{{if $params -}}
++ 路径参数
+
+| 字段 | 类型 | 标签 | 约束 | 描述 |
+| :----------------------: | :---------------------: | :----------------------: | :----------------------: | :----------------------: |
+{{range $paramindex, $param := $params -}}
+| {{$param.Name}} | {{if $param.Field}}[{{jsonType $param.Field}}](#{{$param.Field.ProtoFullName}}){{else}}String{{end}} | 必须 | {{if $param.Field}}{{escape (constraints $param.Field)}}{{end}} | {{if $param.Field}}{{multiline $param.Field.Description}}{{end}} |
+{{end}}
+{{end -}}
 + 请求
 
-{{$message := getMessage $method.RequestName -}}
-| 字段 | 类型 | 标签 | 描述 |
-| :----------------------: | :---------------------: | :----------------------: | :----------------------: |
-{{range $fieldindex, $field := $message.Fields -}}
-| {{$field.JsonName}} | [{{jsonType $field}}](#{{$field.ProtoTypeName}}) | {{$field.JsonLabel}} | {{$field.Description}} |
+| 字段 | 类型 | 标签 | 约束 | 描述 |
+| :----------------------: | :---------------------: | :----------------------: | :----------------------: | :----------------------: |
+{{range $fieldindex, $field := requestFields $method -}}
+| {{strike $field.JsonName $field.Deprecated}} | [{{jsonType $field}}](#{{$field.ProtoFullName}}) | {{label $field}} | {{escape (constraints $field)}} | {{multiline $field.Description}} |
 {{end}}
-**示例**
+{{range $bindingindex, $binding := $method.Bindings -}}
+{{if $binding.HasBody -}}
+**示例**{{if gt (len $method.Bindings) 1}} [{{$binding.Method}}] {{$binding.Path}}{{end}}
 {{codeblock "json"}}
-{{jsonMarshal $message.Name}}
+{{requestJson $method $binding}}
 {{codeblock}}
+{{end -}}
+{{end -}}
 + 响应
 
 {{$message := getMessage $method.ResponseName -}}
-| 字段 | 类型 | 标签 | 描述 |
-| :----------------------: | :---------------------: | :----------------------: | :----------------------: |
+| 字段 | 类型 | 标签 | 约束 | 描述 |
+| :----------------------: | :---------------------: | :----------------------: | :----------------------: | :----------------------: |
 {{range $fieldindex, $field := $message.Fields -}}
-| {{$field.JsonName}} | [{{jsonType $field}}](#{{$field.ProtoTypeName}}) | {{$field.JsonLabel}} | {{$field.Description}} |
+| {{strike $field.JsonName $field.Deprecated}} | [{{jsonType $field}}](#{{$field.ProtoFullName}}) | {{label $field}} | {{escape (constraints $field)}} | {{multiline $field.Description}} |
 {{end}}
 **示例**
 {{codeblock "json"}}
-{{jsonMarshal $message.Name}}
+{{jsonMarshal $method.ResponseName}}
 {{codeblock}}
 ---
 {{end}}
 {{end}}
+{{end}}
 
 ## 结构 <a name="msg"> </a>
 
 | 类型 | 描述 |
 | :----------------------: | :---------------------: |
 {{range $messageindex, $message := .Messages -}}
-| [{{$message.Name}}](#{{$message.Name}}) | {{$message.Description}} |
+| [{{strike $message.Name $message.Deprecated}}](#{{$message.FullName}}) | {{multiline $message.Description}} |
 {{end}}
 ---
 {{range $messageindex, $message := .Messages -}}
-+ ##### {{$message.Name}} <a name="{{$message.Name}}"> </a> [服务](#srv) [结构](#msg) [枚举](#enu)
++ ##### {{strike $message.Name $message.Deprecated}} <a name="{{$message.FullName}}"> </a> [服务](#srv) [结构](#msg) [枚举](#enu)
 {{codeblock}}
 描述: {{$message.Description}}
+{{- if $message.Metadata.Since}}
+版本: since {{$message.Metadata.Since}}
+{{- end}}
+{{- range $oneofindex, $oneof := $message.Oneofs}}
+oneof {{$oneof.Name}}: {{join $oneof.Fields " | "}}    {{$oneof.Description}}
+{{- end}}
 {{codeblock}}
 
-| 字段 | 类型 | 标签 | 描述 |
-| :----------------------: | :---------------------: | :----------------------: | :----------------------: |
+| 字段 | 类型 | 标签 | 约束 | 描述 |
+| :----------------------: | :---------------------: | :----------------------: | :----------------------: | :----------------------: |
 {{range $fieldindex, $field := $message.Fields -}}
-| {{$field.JsonName}} | [{{jsonType $field}}](#{{$field.ProtoTypeName}}) | {{$field.JsonLabel}} | {{$field.Description}} |
+| {{strike $field.JsonName $field.Deprecated}} | [{{jsonType $field}}](#{{$field.ProtoFullName}}) | {{label $field}} | {{escape (constraints $field)}} | {{multiline $field.Description}} |
 {{end}}
 {{end}}
 
@@ -80,11 +150,11 @@ Package {{$packagename}}
 ## 枚举 <a name="enu"> </a>
 
 {{range $enumindex, $enum := .Enums -}}
-+ ##### {{$enum.Name}} <a name="{{$enum.Name}}"> </a> [服务](#srv) [结构](#msg) [枚举](#enu)
++ ##### {{strike $enum.Name $enum.Deprecated}} <a name="{{$enum.FullName}}"> </a> [服务](#srv) [结构](#msg) [枚举](#enu)
 | 键 | 值 | 描述 |
 | :--------------------: | :--------------------: | :---------------------: |
 {{range $fieldindex, $field := $enum.Fields -}}
-| {{$field.Name}} | {{$field.Value}} | {{$enum.Description}}:    {{$field.Description}} |
+| {{strike $field.Name $field.Deprecated}} | {{$field.Value}} | {{multiline $enum.Description}}:    {{multiline $field.Description}} |
 {{end}}
 {{end}}
 ---
@@ -8,7 +8,6 @@ import (
 
 	"github.com/charlesbases/protoc-gen-apidoc/encoder"
 	"github.com/charlesbases/protoc-gen-apidoc/generator"
-	"github.com/charlesbases/protoc-gen-apidoc/logger"
 	"github.com/charlesbases/protoc-gen-apidoc/types"
 )
 
@@ -31,30 +30,40 @@ func NewGenerator(p *types.Package, t Template) generator.Generator {
 }
 
 // Generate code generater
-func (g *Generator) Generate() []byte {
+func (g *Generator) Generate() ([]byte, error) {
 	temp := template.New(string(g.t))
 
 	temp.Funcs(template.FuncMap{
-		"dynamic":     dynamic,
-		"codeblock":   codeblock,
-		"getMessage":  g.getMessage,
-		"jsonType":    g.jsonType,
-		"jsonMarshal": g.jsonMarshal,
-		"increasing":  g.increasing,
-		"polling":     g.polling,
+		"dynamic":       dynamic,
+		"codeblock":     codeblock,
+		"getMessage":    g.getMessage,
+		"jsonType":      g.jsonType,
+		"label":         g.label,
+		"strike":        g.strike,
+		"multiline":     multiline,
+		"firstline":     firstline,
+		"join":          strings.Join,
+		"constraints":   g.constraints,
+		"escape":        escape,
+		"jsonMarshal":   g.jsonMarshal,
+		"requestJson":   g.requestJson,
+		"pathParams":    g.pathParams,
+		"requestFields": g.requestFields,
+		"increasing":    g.increasing,
+		"polling":       g.polling,
 	})
 
 	html, err := temp.Parse(string(g.t))
 	if err != nil {
-		logger.Fatal(err)
+		return nil, err
 	}
 
 	var buffer bytes.Buffer
 	if err := html.Execute(&buffer, g.p); err != nil {
-		logger.Fatal(err)
+		return nil, err
 	}
 
-	return buffer.Bytes()
+	return buffer.Bytes(), nil
 }
 
 // dynamic 动态返回一定长度字符
@@ -70,6 +79,24 @@ func codeblock(languages ...string) template.HTML {
 	return template.HTML("```")
 }
 
+// multiline 多行注释换行使用 <br>, 防止破坏表格
+func multiline(v string) template.HTML {
+	return template.HTML(strings.ReplaceAll(template.HTMLEscapeString(v), "\n", "<br>"))
+}
+
+// firstline 多行注释的第一行
+func firstline(v string) string {
+	if i := strings.Index(v, "\n"); i >= 0 {
+		return v[:i]
+	}
+	return v
+}
+
+// escape 转义 markdown 表格中的 "|"
+func escape(v template.HTML) template.HTML {
+	return template.HTML(strings.ReplaceAll(string(v), "|", "\\|"))
+}
+
 // polling 判断 index 的奇偶, 偶数返回true, 奇数返回false, 制作条纹表格时需要
 func (g *Generator) polling(index int) bool {
 	return index%2 == 0
@@ -98,10 +125,104 @@ func (g *Generator) jsonType(field *types.MessageField) template.HTML {
 	}
 }
 
+// label 字段标签. oneof 中的字段附加 group 名称, proto3 optional 字段标记为可省略, 必须的 repeated 字段标记为必须
+func (g *Generator) label(field *types.MessageField) string {
+	switch {
+	case len(field.ProtoOneof) != 0:
+		return fmt.Sprintf("%s (oneof %s)", field.JsonLabel, field.ProtoOneof)
+	case field.Required && field.JsonLabel == types.JsonLabel_Repeated:
+		return fmt.Sprintf("%s (%s)", field.JsonLabel, types.JsonLabel_Required)
+	case field.ProtoOptional:
+		return fmt.Sprintf("%s (可省略)", field.JsonLabel)
+	default:
+		return string(field.JsonLabel)
+	}
+}
+
+// constraints 字段的校验规则. 例: min_len: 1, max_len: 64
+func (g *Generator) constraints(field *types.MessageField) template.HTML {
+	return template.HTML(template.HTMLEscapeString(strings.Join(field.Rules.Constraints(), ", ")))
+}
+
+// strike deprecated 的名称使用删除线
+func (g *Generator) strike(name string, deprecated bool) template.HTML {
+	if deprecated {
+		return template.HTML("<del>" + template.HTMLEscapeString(name) + "</del>")
+	}
+	return template.HTML(template.HTMLEscapeString(name))
+}
+
 // jsonMarshal json parse for message
 func (g *Generator) jsonMarshal(messageName string) template.HTML {
-	if data := encoder.NewEncoder(g.p).EncodeJson(messageName); len(data) != 0 {
+	return g.example(encoder.NewEncoder(g.p).EncodeJson(messageName))
+}
+
+// requestJson json parse for request body of the binding, without path parameters
+func (g *Generator) requestJson(m *types.ServiceMethod, b *types.HttpBinding) template.HTML {
+	var excludes = make([]string, 0, len(b.PathParams))
+	for _, param := range b.PathParams {
+		excludes = append(excludes, param.Name)
+	}
+	return g.example(encoder.NewEncoder(g.p).EncodeBody(m.RequestName, b.Body, excludes...))
+}
+
+// example 示例 json. 其中可能有 @example 的原始内容, html 中需要转义, 防止破坏页面; markdown 的代码块中保持原样
+func (g *Generator) example(data string) template.HTML {
+	switch {
+	case len(data) == 0:
+		return "null"
+	case g.t == HTML:
+		return template.HTML(template.HTMLEscapeString(data))
+	default:
 		return template.HTML(data)
 	}
-	return "null"
+}
+
+// pathParams path 参数. 多个路由时取并集
+func (g *Generator) pathParams(m *types.ServiceMethod) []*types.PathParam {
+	var (
+		params = make([]*types.PathParam, 0)
+		exists = make(map[string]struct{}, 0)
+	)
+	for _, binding := range m.Bindings {
+		for _, param := range binding.PathParams {
+			if _, found := exists[param.Name]; !found {
+				exists[param.Name] = struct{}{}
+				params = append(params, param)
+			}
+		}
+	}
+	return params
+}
+
+// requestFields 请求结构中除 path 参数外的字段
+func (g *Generator) requestFields(m *types.ServiceMethod) []*types.MessageField {
+	var (
+		excludes = g.commonPathParams(m)
+		fields   = make([]*types.MessageField, 0)
+	)
+	for _, field := range g.getMessage(m.RequestName).Fields {
+		if !types.Contains(excludes, field.ProtoName) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// commonPathParams 所有路由中都存在的 path 参数
+func (g *Generator) commonPathParams(m *types.ServiceMethod) []string {
+	var names = make([]string, 0)
+	for _, param := range g.pathParams(m) {
+		var common = true
+		for _, binding := range m.Bindings {
+			if !binding.IsPathParam(param.Name) {
+				common = false
+				break
+			}
+		}
+		if common {
+			names = append(names, param.Name)
+		}
+	}
+	return names
 }
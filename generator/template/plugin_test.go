@@ -0,0 +1,122 @@
+package template
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"github.com/charlesbases/protoc-gen-apidoc/types/typestest"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// newPackage UpdateRequest 的 GET、PATCH 路由
+func newPackage() *types.Package {
+	var p = typestest.NewPackage(
+		typestest.NewMessage("UpdateRequest", typestest.MessageField("user", "User", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL), typestest.ScalarField("update_mask")),
+		typestest.NewMessage("User", typestest.ScalarField("user_id"), typestest.ScalarField("name")),
+	)
+
+	var binding = func(method types.Method, body string) *types.HttpBinding {
+		return &types.HttpBinding{
+			Path:       "/v1/users/{user.user_id}",
+			Method:     method,
+			Body:       body,
+			PathParams: []*types.PathParam{{Name: "user.user_id", Field: p.FieldByPath("user.UpdateRequest", "user.user_id")}},
+		}
+	}
+	p.AppendService(&types.Service{
+		Name:    "Users",
+		Package: typestest.Package,
+		Methods: []*types.ServiceMethod{{
+			Name:         "Update",
+			RequestName:  "user.UpdateRequest",
+			ResponseName: "user.User",
+			Bindings:     []*types.HttpBinding{binding(types.MethodPatch, "user"), binding(types.MethodGet, "")},
+		}},
+	})
+	return p
+}
+
+func TestRequestJson(t *testing.T) {
+	var (
+		p = newPackage()
+		g = &Generator{p: p, t: Markdown}
+		m = p.Services[0].Methods[0]
+	)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(g.requestJson(m, m.Bindings[0])), &body); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := body["name"]; !found || len(body) != 1 {
+		t.Errorf("body user: got %v, want user without user_id", body)
+	}
+}
+
+func TestRequestExamplePerBinding(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       Template
+		example string
+	}{
+		{name: "markdown", t: Markdown, example: "**示例**"},
+		{name: "html", t: HTML, example: "<h4>示例"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewGenerator(newPackage(), tt.t).Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc = string(data)
+			if !strings.Contains(doc, tt.example+" [PATCH] /v1/users/{user.user_id}") {
+				t.Error("example of the PATCH route not found")
+			}
+			if strings.Contains(doc, tt.example+" [GET]") {
+				t.Error("bodiless GET route should not have a request example")
+			}
+			if strings.Contains(doc, "update_mask&#34;") || strings.Contains(doc, `"update_mask"`) {
+				t.Error("query field update_mask should not be in the request example")
+			}
+		})
+	}
+}
+
+func TestWellKnownResponseExample(t *testing.T) {
+	var p = newPackage()
+	p.Services[0].Methods[0].ResponseName = "google.protobuf.Timestamp"
+
+	data, err := NewGenerator(p, Markdown).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "``` json\n\"1970-01-01T00:00:00Z\"\n```"; !strings.Contains(string(data), want) {
+		t.Errorf("response example of google.protobuf.Timestamp not found, want %q", want)
+	}
+}
+
+func TestExampleEscape(t *testing.T) {
+	var p = newPackage()
+	p.MessageDic["user.User"].Fields[1].Metadata.Example = `["</div><script>alert(1)</script>"]`
+
+	data, err := NewGenerator(p, HTML).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "<script>alert(1)") {
+		t.Error("@example is not escaped in html")
+	}
+	if !strings.Contains(string(data), "&lt;/div&gt;&lt;script&gt;alert(1)") {
+		t.Error("escaped @example not found in html")
+	}
+
+	data, err = NewGenerator(p, Markdown).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "</div><script>alert(1)</script>") {
+		t.Error("@example in markdown code block should be kept as is")
+	}
+}
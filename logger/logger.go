@@ -1,35 +1,170 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/charlesbases/colors"
 )
 
+// prefix 与 protoc 的错误输出格式一致
+const prefix = "--apidoc_out: "
+
+// Level 日志级别. stdout 为 CodeGeneratorResponse 的输出通道, 所以日志只输出到 stderr 或日志文件
+type Level int
+
+const (
+	// LevelWarn 仅在结束时输出警告汇总 (default)
+	LevelWarn Level = iota
+	// LevelInfo 生成的文件等
+	LevelInfo
+	// LevelDebug 解析过程
+	LevelDebug
+)
+
+// levels .
+var levels = map[string]Level{
+	"warn":  LevelWarn,
+	"info":  LevelInfo,
+	"debug": LevelDebug,
+}
+
+// ParseLevel warn、info、debug
+func ParseLevel(s string) (Level, error) {
+	if level, found := levels[strings.ToLower(s)]; found {
+		return level, nil
+	}
+	return LevelWarn, fmt.Errorf(`invalid log level of "%s"`, s)
+}
+
+var (
+	mu     sync.Mutex
+	level            = LevelWarn
+	output io.Writer = os.Stderr
+	// closer 日志文件
+	closer io.Closer
+	// colored 输出到日志文件时不使用颜色
+	colored = true
+
+	// warnings 警告列表. 结束时一起输出
+	warnings = make([]string, 0)
+	// warned 相同的警告只记录一次
+	warned = make(map[string]struct{}, 0)
+)
+
+// Init 设置日志级别与日志文件. filename 为空时输出到 stderr
+func Init(l Level, filename string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	level = l
+	if len(filename) != 0 {
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("open log file failed. %v", err)
+		}
+		output, closer, colored = file, file, false
+	}
+	return nil
+}
+
+// Close 关闭日志文件
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if closer != nil {
+		closer.Close()
+		output, closer, colored = os.Stderr, nil, true
+	}
+}
+
 // Debug .
 func Debug(v ...interface{}) {
-	os.Stdout.WriteString(colors.GreenSprint(v...))
+	write(LevelDebug, colors.GreenSprint, fmt.Sprint(v...))
 }
 
 // Debugf .
 func Debugf(format string, v ...interface{}) {
-	os.Stdout.WriteString(colors.GreenSprintf(format, v...))
+	write(LevelDebug, colors.GreenSprint, fmt.Sprintf(format, v...))
 }
 
-// Fatal .
-func Fatal(v ...interface{}) {
-	stderr(colors.RedSprint(v...))
+// Info .
+func Info(v ...interface{}) {
+	write(LevelInfo, colors.BlueSprint, fmt.Sprint(v...))
 }
 
-// Fatalf .
-func Fatalf(format string, v ...interface{}) {
-	stderr(colors.RedSprintf(format, v...))
+// Infof .
+func Infof(format string, v ...interface{}) {
+	write(LevelInfo, colors.BlueSprint, fmt.Sprintf(format, v...))
+}
+
+// Warn 记录警告, 在 Summary 中输出
+func Warn(v ...interface{}) {
+	warn(fmt.Sprint(v...))
+}
+
+// Warnf .
+func Warnf(format string, v ...interface{}) {
+	warn(fmt.Sprintf(format, v...))
+}
+
+// WarnAt 带有位置的警告. 例: user/v1/user.proto:12:3: message
+func WarnAt(pos fmt.Stringer, format string, v ...interface{}) {
+	if p := pos.String(); len(p) != 0 {
+		format = p + ": " + format
+	}
+	warn(fmt.Sprintf(format, v...))
+}
+
+// Summary 输出警告汇总
+func Summary() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(warnings) == 0 {
+		return
+	}
+
+	var br strings.Builder
+	br.WriteString(sprint(colors.YellowSprint, prefix+fmt.Sprintf("%d warning(s)", len(warnings))))
+	br.WriteString("\n")
+	for _, w := range warnings {
+		br.WriteString("  ")
+		br.WriteString(w)
+		br.WriteString("\n")
+	}
+	io.WriteString(output, br.String())
+}
+
+// warn .
+func warn(msg string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, found := warned[msg]; !found {
+		warned[msg] = struct{}{}
+		warnings = append(warnings, msg)
+	}
+}
+
+// write .
+func write(l Level, color func(a ...interface{}) string, msg string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l <= level {
+		io.WriteString(output, sprint(color, prefix)+msg+"\n")
+	}
 }
 
-// stderr .
-func stderr(err string) {
-	os.Stderr.WriteString(colors.RedSprint("--apidoc_out: "))
-	os.Stderr.WriteString(err)
-	os.Stderr.WriteString("\n")
-	os.Exit(1)
+// sprint 输出到日志文件时不使用颜色
+func sprint(color func(a ...interface{}) string, s string) string {
+	if colored {
+		return color(s)
+	}
+	return s
 }
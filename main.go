@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/charlesbases/protoc-gen-apidoc/conf"
 	"github.com/charlesbases/protoc-gen-apidoc/generator"
 	"github.com/charlesbases/protoc-gen-apidoc/generator/postman"
@@ -13,8 +15,11 @@ import (
 )
 
 func main() {
-	protoc.Plugin(func(p *types.Package) *pluginpb.CodeGeneratorResponse {
-		var rsp = new(pluginpb.CodeGeneratorResponse)
+	protoc.Plugin(func(p *types.Package) (*pluginpb.CodeGeneratorResponse, error) {
+		var (
+			rsp  = new(pluginpb.CodeGeneratorResponse)
+			errs types.Errors
+		)
 
 		for _, dt := range conf.Get().Document {
 			var gen generator.Generator
@@ -28,10 +33,19 @@ func main() {
 			case types.DocumentTypePostman:
 				gen = postman.NewGenerator(p)
 			default:
-				logger.Fatalf(`invalid type of "%s"`, dt.Type)
+				errs.Append(fmt.Errorf(`invalid type of "%s"`, dt.Type))
+				continue
+			}
+
+			data, err := gen.Generate()
+			if err != nil {
+				errs.Append(err)
+				continue
 			}
 
-			if data := gen.Generate(); len(data) != 0 {
+			if len(data) != 0 {
+				logger.Infof("generate %s (%d bytes)", dt.File, len(data))
+
 				var content = string(data)
 				rsp.File = append(rsp.File, &pluginpb.CodeGeneratorResponse_File{
 					Name:    &dt.File,
@@ -40,6 +54,6 @@ func main() {
 			}
 		}
 
-		return rsp
+		return rsp, errs.Err()
 	})
 }
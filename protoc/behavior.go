@@ -0,0 +1,67 @@
+package protoc
+
+import (
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// google/api/field_behavior.proto
+//
+//	extend google.protobuf.FieldOptions {
+//	  repeated google.api.FieldBehavior field_behavior = 1052 [packed = false];
+//	}
+const googleApiFieldBehavior protowire.Number = 1052
+
+// fieldBehavior google.api.FieldBehavior
+type fieldBehavior uint64
+
+// fieldBehaviorRequired google.api.FieldBehavior.REQUIRED
+const fieldBehaviorRequired fieldBehavior = 2
+
+// parseGoogleApiFieldBehavior google.api.field_behavior in FieldOptions
+//
+// google/api/field_behavior.proto 未注册到当前程序中, 所以从 unknown fields 中解析
+func parseGoogleApiFieldBehavior(opts *descriptorpb.FieldOptions) []fieldBehavior {
+	if opts == nil {
+		return nil
+	}
+
+	var behaviors = make([]fieldBehavior, 0)
+	rangeFields(opts.ProtoReflect().GetUnknown(), func(num protowire.Number, typ protowire.Type, data []byte) {
+		if num != googleApiFieldBehavior {
+			return
+		}
+
+		switch typ {
+		case protowire.VarintType:
+			if v, n := protowire.ConsumeVarint(data); n > 0 {
+				behaviors = append(behaviors, fieldBehavior(v))
+			}
+		case protowire.BytesType:
+			// packed
+			for len(data) > 0 {
+				v, n := protowire.ConsumeVarint(data)
+				if n < 0 {
+					break
+				}
+				behaviors = append(behaviors, fieldBehavior(v))
+				data = data[n:]
+			}
+		}
+	})
+	return behaviors
+}
+
+// isRequired proto2 required, google.api.field_behavior = REQUIRED or validation required
+func isRequired(protoField *descriptorpb.FieldDescriptorProto, rules *types.FieldRules) bool {
+	if protoField.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED || (rules != nil && rules.Required) {
+		return true
+	}
+	for _, behavior := range parseGoogleApiFieldBehavior(protoField.GetOptions()) {
+		if behavior == fieldBehaviorRequired {
+			return true
+		}
+	}
+	return false
+}
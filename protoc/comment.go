@@ -2,6 +2,7 @@ package protoc
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charlesbases/protoc-gen-apidoc/types"
 )
@@ -33,6 +34,8 @@ const (
 	COMMENT_PATH_MESSAGE_ENUM = 4
 	// COMMENT_PATH_MESSAGE_EXTENSION message.ectension
 	COMMENT_PATH_MESSAGE_EXTENSION = 6
+	// COMMENT_PATH_MESSAGE_ONEOF message.oneof
+	COMMENT_PATH_MESSAGE_ONEOF = 8
 
 	// tag numbers in EnumDescriptorProto
 
@@ -46,27 +49,68 @@ const (
 )
 
 type (
-	comments map[string]*comment
+	// comments comments and locations in proto file
+	comments struct {
+		// file proto file
+		file string
+		// locations map[path]*comment
+		locations map[string]*comment
+		// spans map[path][start line, start column, (end line), end column]. 从 0 开始
+		spans map[string][]int32
+	}
 
 	comment struct {
 		leading  string
 		trailing string
 		detached []string
+		// metadata directives in leading comment
+		metadata types.Metadata
 	}
 )
 
-// comment get comment by path
+// comment get comment by path. 优先使用前置注释, 其次为后置注释, 都没有时使用名称
 func (cs comments) comment(name string, paths ...int) string {
-	if comment, found := cs[fmt.Sprintf("%v", paths)]; found && comment.leading != "" {
-		return comment.leading
+	if comment, found := cs.locations[fmt.Sprintf("%v", paths)]; found {
+		switch {
+		case comment.leading != "":
+			return comment.leading
+		case comment.trailing != "":
+			return comment.trailing
+		}
 	}
 	return name
 }
 
+// detached get detached comments by path. 多段注释以空行连接
+func (cs comments) detached(paths ...int) string {
+	if comment, found := cs.locations[fmt.Sprintf("%v", paths)]; found {
+		return strings.Join(comment.detached, "\n\n")
+	}
+	return ""
+}
+
+// metadata get comment directives by path
+func (cs comments) metadata(paths ...int) types.Metadata {
+	if comment, found := cs.locations[fmt.Sprintf("%v", paths)]; found {
+		return comment.metadata
+	}
+	return types.Metadata{}
+}
+
+// position get position in proto file by path
+func (cs comments) position(paths ...int) types.Position {
+	var pos = types.Position{File: cs.file}
+	if span := cs.spans[fmt.Sprintf("%v", paths)]; len(span) >= 2 {
+		pos.Line, pos.Column = int(span[0])+1, int(span[1])+1
+	}
+	return pos
+}
+
 // newPackage .
-func newPackage(name string) *types.Package {
+func newPackage(packages []string) *types.Package {
 	return &types.Package{
-		Name:       name,
+		Name:       strings.Join(packages, ", "),
+		Packages:   packages,
 		Version:    version(),
 		Services:   make([]*types.Service, 0),
 		Enums:      make([]*types.Enum, 0),
@@ -89,8 +133,17 @@ func newService(name, desc string) *types.Service {
 func newServiceMethod(name, desc string) *types.ServiceMethod {
 	return &types.ServiceMethod{
 		Name:        name,
-		Method:      types.MethodPost,
 		Description: desc,
+		Bindings:    make([]*types.HttpBinding, 0, 1),
+	}
+}
+
+// newHttpBinding .
+func newHttpBinding(path string, method types.Method, body string) *types.HttpBinding {
+	return &types.HttpBinding{
+		Path:   path,
+		Method: method,
+		Body:   body,
 	}
 }
 
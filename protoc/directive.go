@@ -0,0 +1,116 @@
+package protoc
+
+import (
+	"strings"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+)
+
+// comment directives. 指令以 "@" 开头并独占一行. @summary、@example 之后的非指令行为该指令值的续行,
+// 其他指令只有一行, 之后的非指令行仍为注释
+//
+//	// 获取用户
+//	// @summary 获取用户信息
+//	// @tag user
+//	// @since v1.2.0
+//	rpc GetUser (GetUserRequest) returns (User);
+const (
+	// directiveSummary 接口简介
+	directiveSummary = "@summary"
+	// directiveExample 示例. 例: @example "alice"
+	directiveExample = "@example"
+	// directiveIgnore 不生成文档
+	directiveIgnore = "@ignore"
+	// directiveTag 接口分组, 可以有多个
+	directiveTag = "@tag"
+	// directiveSince 起始版本
+	directiveSince = "@since"
+	// directiveInternal 内部接口
+	directiveInternal = "@internal"
+	// directiveOwner 负责人
+	directiveOwner = "@owner"
+)
+
+// multilines 值可以有多行的指令
+var multilines = map[string]struct{}{
+	directiveSummary: {},
+	directiveExample: {},
+}
+
+// parseDirectives 解析注释中的指令, 返回去除指令后的注释
+func parseDirectives(text string) (string, types.Metadata) {
+	var (
+		metadata types.Metadata
+		lines    = make([]string, 0)
+
+		// directive 当前指令
+		directive string
+		// values 当前指令的值
+		values []string
+	)
+
+	// flush 保存当前指令
+	var flush = func() {
+		value := strings.TrimSpace(strings.Join(values, "\n"))
+
+		switch directive {
+		case directiveSummary:
+			metadata.Summary = value
+		case directiveExample:
+			metadata.Example = value
+		case directiveIgnore:
+			metadata.Ignore = true
+		case directiveTag:
+			if len(value) != 0 {
+				metadata.Tags = append(metadata.Tags, value)
+			}
+		case directiveSince:
+			metadata.Since = value
+		case directiveInternal:
+			metadata.Internal = true
+		case directiveOwner:
+			metadata.Owner = value
+		}
+		directive, values = "", nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if name, value, found := directiveLine(line); found {
+			flush()
+			directive, values = name, []string{value}
+			if _, found := multilines[name]; !found {
+				flush()
+			}
+			continue
+		}
+
+		if len(directive) != 0 {
+			values = append(values, line)
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+
+	return strings.TrimSpace(strings.Join(lines, "\n")), metadata
+}
+
+// directiveLine 判断注释行是否为指令. 未知的 "@xxx" 按普通注释处理
+func directiveLine(line string) (name string, value string, found bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "@") {
+		return "", "", false
+	}
+
+	name, value = line, ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		name, value = line[:i], strings.TrimSpace(line[i+1:])
+	}
+
+	switch name {
+	case directiveSummary, directiveExample, directiveIgnore, directiveTag, directiveSince, directiveInternal, directiveOwner:
+		return name, value, true
+	default:
+		return "", "", false
+	}
+}
@@ -0,0 +1,75 @@
+package protoc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+)
+
+func TestParseDirectives(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		desc     string
+		metadata types.Metadata
+	}{
+		{
+			name: "no directive",
+			text: "获取用户\n根据 uid 获取",
+			desc: "获取用户\n根据 uid 获取",
+		},
+		{
+			name:     "flag directive before description",
+			text:     "@internal\nUpdates the user permanently.",
+			desc:     "Updates the user permanently.",
+			metadata: types.Metadata{Internal: true},
+		},
+		{
+			name:     "ignore between description lines",
+			text:     "first line\n@ignore\nsecond line",
+			desc:     "first line\nsecond line",
+			metadata: types.Metadata{Ignore: true},
+		},
+		{
+			name:     "single line directives",
+			text:     "获取用户\n@tag user\n@since v1.2.0\n@owner alice\nmore description",
+			desc:     "获取用户\nmore description",
+			metadata: types.Metadata{Tags: []string{"user"}, Since: "v1.2.0", Owner: "alice"},
+		},
+		{
+			name:     "multiline summary",
+			text:     "获取用户\n@summary 根据 uid\n获取用户信息\n@tag user",
+			desc:     "获取用户",
+			metadata: types.Metadata{Summary: "根据 uid\n获取用户信息", Tags: []string{"user"}},
+		},
+		{
+			name:     "multiline example",
+			text:     "用户\n@example {\n  \"uid\": 1\n}",
+			desc:     "用户",
+			metadata: types.Metadata{Example: "{\n  \"uid\": 1\n}"},
+		},
+		{
+			name:     "multiple tags",
+			text:     "@tag a\n@tag b",
+			metadata: types.Metadata{Tags: []string{"a", "b"}},
+		},
+		{
+			name: "unknown directive is description",
+			text: "mail me @someone\n@unknown value",
+			desc: "mail me @someone\n@unknown value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, metadata := parseDirectives(tt.text)
+			if desc != tt.desc {
+				t.Errorf("description: got %q, want %q", desc, tt.desc)
+			}
+			if !reflect.DeepEqual(metadata, tt.metadata) {
+				t.Errorf("metadata: got %+v, want %+v", metadata, tt.metadata)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package protoc
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// google/api/annotations.proto
+//
+//	extend google.protobuf.MethodOptions {
+//	  HttpRule http = 72295728;
+//	}
+const googleApiHttp protowire.Number = 72295728
+
+// tag numbers in google.api.HttpRule
+const (
+	httpRuleGet                protowire.Number = 2
+	httpRulePut                protowire.Number = 3
+	httpRulePost               protowire.Number = 4
+	httpRuleDelete             protowire.Number = 5
+	httpRulePatch              protowire.Number = 6
+	httpRuleBody               protowire.Number = 7
+	httpRuleCustom             protowire.Number = 8
+	httpRuleAdditionalBindings protowire.Number = 11
+	httpRuleResponseBody       protowire.Number = 12
+
+	// google.api.CustomHttpPattern
+	httpCustomKind protowire.Number = 1
+	httpCustomPath protowire.Number = 2
+)
+
+// httpRule google.api.HttpRule
+type httpRule struct {
+	// method http method. get, put, post, delete, patch or custom kind
+	method string
+	// path uri template
+	path string
+	// body request field mapped to the http body
+	body string
+	// responseBody response field mapped to the http body
+	responseBody string
+	// additionalBindings additional http bindings for the rpc
+	additionalBindings []*httpRule
+}
+
+// parseGoogleApiHttp google.api.http in MethodOptions
+//
+// google/api/annotations.proto 未注册到当前程序中, 所以从 unknown fields 中解析
+func parseGoogleApiHttp(opts *descriptorpb.MethodOptions) *httpRule {
+	if opts == nil {
+		return nil
+	}
+
+	var rule *httpRule
+	rangeFields(opts.ProtoReflect().GetUnknown(), func(num protowire.Number, typ protowire.Type, data []byte) {
+		if num == googleApiHttp && typ == protowire.BytesType {
+			rule = decodeHttpRule(data)
+		}
+	})
+	return rule
+}
+
+// decodeHttpRule .
+func decodeHttpRule(b []byte) *httpRule {
+	var rule = new(httpRule)
+
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		if typ != protowire.BytesType {
+			return
+		}
+
+		switch num {
+		case httpRuleGet:
+			rule.method, rule.path = "get", string(data)
+		case httpRulePut:
+			rule.method, rule.path = "put", string(data)
+		case httpRulePost:
+			rule.method, rule.path = "post", string(data)
+		case httpRuleDelete:
+			rule.method, rule.path = "delete", string(data)
+		case httpRulePatch:
+			rule.method, rule.path = "patch", string(data)
+		case httpRuleCustom:
+			rangeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) {
+				switch num {
+				case httpCustomKind:
+					rule.method = string(data)
+				case httpCustomPath:
+					rule.path = string(data)
+				}
+			})
+		case httpRuleBody:
+			rule.body = string(data)
+		case httpRuleResponseBody:
+			rule.responseBody = string(data)
+		case httpRuleAdditionalBindings:
+			rule.additionalBindings = append(rule.additionalBindings, decodeHttpRule(data))
+		}
+	})
+	return rule
+}
+
+// rangeFields 遍历 protobuf 编码数据中的字段. BytesType 字段返回内容, 其他类型返回原始编码
+func rangeFields(b []byte, fn func(num protowire.Number, typ protowire.Type, data []byte)) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return
+		}
+		b = b[n:]
+
+		var data []byte
+		if typ == protowire.BytesType {
+			data, n = protowire.ConsumeBytes(b)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n >= 0 {
+				data = b[:n]
+			}
+		}
+		if n < 0 {
+			return
+		}
+		b = b[n:]
+
+		fn(num, typ, data)
+	}
+}
@@ -0,0 +1,147 @@
+package protoc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+)
+
+// path template syntax. google/api/http.proto
+//
+//	Template = "/" | "/" Segments [ "/" ] [ Verb ] ;
+//	Segments = Segment { "/" Segment } ;
+//	Segment  = "*" | "**" | LITERAL | Variable ;
+//	Variable = "{" FieldPath [ "=" Segments ] "}" ;
+//	FieldPath = IDENT { "." IDENT } ;
+//	Verb     = ":" LITERAL ;
+type pathParser struct {
+	path string
+	pos  int
+}
+
+// parsePathTemplate .
+func parsePathTemplate(path string) (*types.PathTemplate, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf(`invalid path template "%s": must start with "/"`, path)
+	}
+
+	// 根路径
+	if path == "/" {
+		return &types.PathTemplate{Segments: make([]*types.PathSegment, 0)}, nil
+	}
+
+	// 忽略末尾的 "/". 例: /v1/users/ => /v1/users
+	var p = &pathParser{path: strings.TrimSuffix(path, "/"), pos: 1}
+
+	segments, err := p.segments(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var template = &types.PathTemplate{Segments: segments}
+
+	// verb
+	if p.consume(':') {
+		if template.Verb = p.literal(); len(template.Verb) == 0 {
+			return nil, p.errorf("verb expected")
+		}
+	}
+
+	if p.pos != len(p.path) {
+		return nil, p.errorf("unexpected %q", p.path[p.pos])
+	}
+	return template, nil
+}
+
+// segments .
+func (p *pathParser) segments(nested bool) ([]*types.PathSegment, error) {
+	var segments = make([]*types.PathSegment, 0)
+	for {
+		segment, err := p.segment(nested)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+
+		if !p.consume('/') {
+			return segments, nil
+		}
+	}
+}
+
+// segment .
+func (p *pathParser) segment(nested bool) (*types.PathSegment, error) {
+	switch {
+	case strings.HasPrefix(p.path[p.pos:], "**"):
+		p.pos += 2
+		return &types.PathSegment{Literal: "**"}, nil
+	case p.consume('*'):
+		return &types.PathSegment{Literal: "*"}, nil
+	case p.consume('{'):
+		if nested {
+			return nil, p.errorf("nested variable")
+		}
+		return p.variable()
+	default:
+		literal := p.literal()
+		if len(literal) == 0 {
+			return nil, p.errorf("segment expected")
+		}
+		return &types.PathSegment{Literal: literal}, nil
+	}
+}
+
+// variable .
+func (p *pathParser) variable() (*types.PathSegment, error) {
+	var start = p.pos
+	for p.pos < len(p.path) && isFieldPathChar(p.path[p.pos]) {
+		p.pos++
+	}
+
+	var segment = &types.PathSegment{Variable: p.path[start:p.pos]}
+	if len(segment.Variable) == 0 || strings.HasPrefix(segment.Variable, ".") || strings.HasSuffix(segment.Variable, ".") {
+		return nil, p.errorf("invalid field path %q", segment.Variable)
+	}
+
+	if p.consume('=') {
+		pattern, err := p.segments(true)
+		if err != nil {
+			return nil, err
+		}
+		segment.Pattern = pattern
+	}
+
+	if !p.consume('}') {
+		return nil, p.errorf(`"}" expected`)
+	}
+	return segment, nil
+}
+
+// literal .
+func (p *pathParser) literal() string {
+	var start = p.pos
+	for p.pos < len(p.path) && !strings.ContainsRune("/{}=:*", rune(p.path[p.pos])) {
+		p.pos++
+	}
+	return p.path[start:p.pos]
+}
+
+// consume .
+func (p *pathParser) consume(c byte) bool {
+	if p.pos < len(p.path) && p.path[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// errorf .
+func (p *pathParser) errorf(format string, v ...interface{}) error {
+	return fmt.Errorf(`invalid path template "%s" at %d: %s`, p.path, p.pos, fmt.Sprintf(format, v...))
+}
+
+// isFieldPathChar .
+func isFieldPathChar(c byte) bool {
+	return c == '_' || c == '.' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
@@ -0,0 +1,101 @@
+package protoc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+)
+
+// literal .
+func literal(v string) *types.PathSegment {
+	return &types.PathSegment{Literal: v}
+}
+
+// variable .
+func variable(name string, pattern ...*types.PathSegment) *types.PathSegment {
+	return &types.PathSegment{Variable: name, Pattern: pattern}
+}
+
+func TestParsePathTemplate(t *testing.T) {
+	tests := []struct {
+		path     string
+		segments []*types.PathSegment
+		verb     string
+	}{
+		{path: "/", segments: []*types.PathSegment{}},
+		{path: "/v1/users", segments: []*types.PathSegment{literal("v1"), literal("users")}},
+		{path: "/v1/users/", segments: []*types.PathSegment{literal("v1"), literal("users")}},
+		{path: "/v1/*/users", segments: []*types.PathSegment{literal("v1"), literal("*"), literal("users")}},
+		{path: "/v1/**", segments: []*types.PathSegment{literal("v1"), literal("**")}},
+		{path: "/v1/users/{user_id}", segments: []*types.PathSegment{literal("v1"), literal("users"), variable("user_id")}},
+		{path: "/v1/users/{user.user_id}", segments: []*types.PathSegment{literal("v1"), literal("users"), variable("user.user_id")}},
+		{
+			path:     "/v1/{name=projects/*/books/*}",
+			segments: []*types.PathSegment{literal("v1"), variable("name", literal("projects"), literal("*"), literal("books"), literal("*"))},
+		},
+		{path: "/v1/{name=files/**}", segments: []*types.PathSegment{literal("v1"), variable("name", literal("files"), literal("**"))}},
+		{path: "/v1/users:batchGet", segments: []*types.PathSegment{literal("v1"), literal("users")}, verb: "batchGet"},
+		{path: "/v1/users/{user_id}:publish", segments: []*types.PathSegment{literal("v1"), literal("users"), variable("user_id")}, verb: "publish"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			template, err := parsePathTemplate(tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(template.Segments, tt.segments) {
+				t.Errorf("segments: got %s, want %s", segmentsString(template.Segments), segmentsString(tt.segments))
+			}
+			if template.Verb != tt.verb {
+				t.Errorf("verb: got %q, want %q", template.Verb, tt.verb)
+			}
+		})
+	}
+}
+
+func TestParsePathTemplateInvalid(t *testing.T) {
+	tests := []struct {
+		path string
+		err  string
+	}{
+		{path: "", err: `must start with "/"`},
+		{path: "v1/users", err: `must start with "/"`},
+		{path: "//", err: "segment expected"},
+		{path: "/v1//users", err: "segment expected"},
+		{path: "/v1/{user_id", err: `"}" expected`},
+		{path: "/v1/{}", err: "invalid field path"},
+		{path: "/v1/{.id}", err: "invalid field path"},
+		{path: "/v1/{id.}", err: "invalid field path"},
+		{path: "/v1/{name=projects/{id}}", err: "nested variable"},
+		{path: "/v1/users:", err: "verb expected"},
+		{path: "/v1/users}", err: `unexpected '}'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := parsePathTemplate(tt.path)
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.err)
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %q, want error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// segmentsString .
+func segmentsString(segments []*types.PathSegment) string {
+	var list = make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment.IsVariable() {
+			list = append(list, "{"+segment.Variable+"="+segment.PatternString()+"}")
+		} else {
+			list = append(list, segment.Literal)
+		}
+	}
+	return "/" + strings.Join(list, "/")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
@@ -19,39 +20,84 @@ import (
 )
 
 // Plugin .
-func Plugin(fn func(p *types.Package) *pluginpb.CodeGeneratorResponse) {
+func Plugin(fn func(p *types.Package) (*pluginpb.CodeGeneratorResponse, error)) {
+	rsp, err := run(fn)
+	if err != nil {
+		// 错误通过 CodeGeneratorResponse.Error 报告给 protoc
+		rsp = &pluginpb.CodeGeneratorResponse{Error: proto.String(err.Error())}
+	}
+
+	// 警告汇总. 日志只输出到 stderr 或日志文件, stdout 仅用于 CodeGeneratorResponse
+	logger.Summary()
+	logger.Close()
+
+	stdout(rsp)
+}
+
+// run .
+func run(fn func(p *types.Package) (*pluginpb.CodeGeneratorResponse, error)) (*pluginpb.CodeGeneratorResponse, error) {
 	var buff = new(bytes.Buffer)
 	if _, err := io.Copy(buff, os.Stdin); err != nil {
-		logger.Fatal("read os.Stdin failed. ", err)
+		return nil, fmt.Errorf("read os.Stdin failed. %v", err)
 	}
 
 	var req = new(pluginpb.CodeGeneratorRequest)
 	if err := proto.Unmarshal(buff.Bytes(), req); err != nil {
-		logger.Fatal("unmarshal os.Stdin failed. ", err)
+		return nil, fmt.Errorf("unmarshal os.Stdin failed. %v", err)
 	}
 	if len(req.GetFileToGenerate()) == 0 {
-		logger.Fatal("no file to generate")
+		return nil, fmt.Errorf("no file to generate")
 	}
 
 	// 解析配置参数
-	conf.Parse(req.GetParameter())
+	if err := conf.Parse(req.GetParameter()); err != nil {
+		return nil, err
+	}
+	if err := logger.Init(conf.Get().Log.Level(), conf.Get().LogFile); err != nil {
+		return nil, err
+	}
 
 	// proto 解析
-	stdout(fn(parse(req)))
+	p, err := parse(req)
+	if err != nil {
+		return nil, err
+	}
+	return fn(p)
 }
 
 // stdout .
 func stdout(rsp *pluginpb.CodeGeneratorResponse) {
-	if data, err := proto.Marshal(rsp); err != nil {
-		logger.Fatal(err)
-	} else {
-		os.Stdout.Write(data)
+	// 支持 proto3 optional, 否则 protoc 会拒绝含有 optional 字段的文件
+	rsp.SupportedFeatures = proto.Uint64(uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL))
+
+	data, err := proto.Marshal(rsp)
+	if err != nil {
+		// 生成结果序列化失败时, 错误仍然通过 CodeGeneratorResponse.Error 报告给 protoc
+		data, _ = proto.Marshal(&pluginpb.CodeGeneratorResponse{
+			Error:             proto.String(fmt.Sprintf("marshal CodeGeneratorResponse failed. %v", err)),
+			SupportedFeatures: rsp.SupportedFeatures,
+		})
 	}
+	os.Stdout.Write(data)
 }
 
 // parse 解析 proto 文件
-func parse(req *pluginpb.CodeGeneratorRequest) *types.Package {
-	var p = newPackage(req.GetProtoFile()[0].GetPackage())
+func parse(req *pluginpb.CodeGeneratorRequest) (*types.Package, error) {
+	// 需要生成文档的 proto 文件
+	var generate = make(map[string]struct{}, len(req.GetFileToGenerate()))
+	for _, name := range req.GetFileToGenerate() {
+		generate[name] = struct{}{}
+	}
+
+	var packages = make([]string, 0)
+	for _, file := range req.GetProtoFile() {
+		if _, found := generate[file.GetName()]; found && !types.Contains(packages, file.GetPackage()) {
+			packages = append(packages, file.GetPackage())
+		}
+	}
+	sort.Strings(packages)
+
+	var p = newPackage(packages)
 
 	var swg = sync.WaitGroup{}
 	swg.Add(len(req.GetProtoFile()))
@@ -59,32 +105,35 @@ func parse(req *pluginpb.CodeGeneratorRequest) *types.Package {
 	for fidx := range req.GetProtoFile() {
 		go func(file *descriptorpb.FileDescriptorProto) {
 			if !strings.HasPrefix(file.GetPackage(), "google.protobuf") {
+				logger.Debugf("parse file %s", file.GetName())
+
 				// parse comment
-				var cs = parseComments(file.SourceCodeInfo)
+				var cs = parseComments(file)
+				var pkg = file.GetPackage()
 
 				// parse enum
 				for idx, protoEnum := range file.GetEnumType() {
-					p.AppendEnum(cs.parseEnum(protoEnum, COMMENT_PATH_ENUM, idx))
+					enum := cs.parseEnum(protoEnum, pkg, COMMENT_PATH_ENUM, idx)
+					enum.File = file.GetName()
+					p.AppendEnum(enum)
 				}
 
 				// parse message
 				for midx, protoMessage := range file.GetMessageType() {
-					var paths = []int{COMMENT_PATH_MESSAGE, midx}
-
-					for eidx, protoEnum := range protoMessage.GetEnumType() {
-						p.AppendEnum(cs.parseMessageEnum(protoEnum, protoMessage.GetName(), append(paths, COMMENT_PATH_MESSAGE_ENUM, eidx)...))
-					}
-
-					for nidx, protoNested := range protoMessage.GetNestedType() {
-						p.AppendMessage(cs.parseMessageNested(protoNested, protoMessage.GetName(), append(paths, COMMENT_PATH_MESSAGE_MESSAGE, nidx)...))
-					}
-
-					p.AppendMessage(cs.parseMessage(protoMessage, paths...))
+					cs.parseMessageTree(p, file, protoMessage, nil, COMMENT_PATH_MESSAGE, midx)
 				}
 
-				// parse service
-				for idx, protoService := range file.GetService() {
-					p.Services = append(p.Services, cs.parseService(protoService, COMMENT_PATH_SERVICE, idx))
+				// parse service. 仅解析 FileToGenerate 中的 service
+				if _, found := generate[file.GetName()]; found {
+					for idx, protoService := range file.GetService() {
+						service := cs.parseService(protoService, pkg, COMMENT_PATH_SERVICE, idx)
+						service.File = file.GetName()
+						if service.Metadata.Ignore {
+							logger.Debugf("service %s is ignored", service.Name)
+							continue
+						}
+						p.AppendService(service)
+					}
 				}
 			}
 
@@ -94,45 +143,269 @@ func parse(req *pluginpb.CodeGeneratorRequest) *types.Package {
 
 	swg.Wait()
 
-	return p.Sort()
+	// 移除未被引用的依赖结构
+	parseReachable(p, generate)
+
+	// 结构名称
+	parseDisplayNames(p)
+
+	// 解析错误一起报告
+	var errs types.Errors
+
+	// 字段类型
+	errs.Append(parseFieldTypes(p))
+
+	// path 参数
+	errs.Append(parsePathParams(p))
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	// 路由前缀
+	parsePrefix(p)
+
+	return p.Sort(), nil
+}
+
+// parsePrefix 所有 service 的路由前缀相同时, 作为 Package 的路由前缀
+func parsePrefix(p *types.Package) {
+	for idx, srv := range p.Services {
+		if idx != 0 && srv.Prefix != p.Prefix {
+			p.Prefix = ""
+			return
+		}
+		p.Prefix = srv.Prefix
+	}
+}
+
+// parseReachable 仅保留 FileToGenerate 中的结构, 以及其中的接口、结构引用到的依赖文件中的结构
+func parseReachable(p *types.Package, generate map[string]struct{}) {
+	var (
+		reachable = make(map[string]struct{}, len(p.Messages)+len(p.Enums))
+		queue     = make([]string, 0, len(p.Messages))
+	)
+
+	for _, srv := range p.Services {
+		for _, method := range srv.Methods {
+			queue = append(queue, method.RequestName, method.ResponseName)
+		}
+	}
+	for _, mess := range p.Messages {
+		// @ignore 的结构仅在被引用时生成
+		if _, found := generate[mess.File]; found && !mess.Metadata.Ignore {
+			queue = append(queue, mess.FullName)
+		}
+	}
+	for _, enum := range p.Enums {
+		if _, found := generate[enum.File]; found {
+			reachable[enum.FullName] = struct{}{}
+		}
+	}
+
+	for len(queue) != 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if _, found := reachable[name]; found {
+			continue
+		}
+		reachable[name] = struct{}{}
+
+		if mess, found := p.MessageDic[name]; found {
+			for _, field := range mess.Fields {
+				if len(field.ProtoFullName) != 0 {
+					queue = append(queue, field.ProtoFullName)
+				}
+			}
+		}
+	}
+
+	var messages = make([]*types.Message, 0, len(p.Messages))
+	for _, mess := range p.Messages {
+		if _, found := reachable[mess.FullName]; found {
+			messages = append(messages, mess)
+		} else {
+			delete(p.MessageDic, mess.FullName)
+		}
+	}
+	p.Messages = messages
+
+	var enums = make([]*types.Enum, 0, len(p.Enums))
+	for _, enum := range p.Enums {
+		if _, found := reachable[enum.FullName]; found {
+			enums = append(enums, enum)
+		} else {
+			delete(p.EnumDic, enum.FullName)
+		}
+	}
+	p.Enums = enums
+}
+
+// parseDisplayNames 结构显示名称. 默认使用短名称, 短名称重复时使用 fully-qualified name.
+// 例: user.v1.User、order.v1.User => user.v1.User、order.v1.User; user.v1.Foo.Bar、user.v1.Foo_Bar => user.v1.Foo.Bar、user.v1.Foo_Bar
+func parseDisplayNames(p *types.Package) {
+	var counter = make(map[string]int, len(p.Messages)+len(p.Enums))
+	for _, mess := range p.Messages {
+		counter[mess.Name]++
+	}
+	for _, enum := range p.Enums {
+		counter[enum.Name]++
+	}
+
+	for _, mess := range p.Messages {
+		if counter[mess.Name] > 1 {
+			mess.Name = mess.FullName
+		}
+	}
+	for _, enum := range p.Enums {
+		if counter[enum.Name] > 1 {
+			enum.Name = enum.FullName
+		}
+	}
+}
+
+// parseFieldTypes 关联字段类型. 字段引用的结构可能在其他 proto 文件中, 所以在所有文件解析完成后处理
+func parseFieldTypes(p *types.Package) error {
+	var errs types.Errors
+	for _, mess := range p.Messages {
+		for _, field := range mess.Fields {
+			switch field.ProtoType {
+			case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+				if def, found := p.MessageDic[field.ProtoFullName]; found {
+					field.ProtoTypeName = def.Name
+					field.ProtoPackagePath = def.Package
+					field.ProtoMapEntry = def.MapEntry
+				} else if wkt, found := types.WellKnown(field.ProtoFullName); found {
+					// google.protobuf 未解析, well-known type 单独映射
+					field.ProtoTypeName = wkt.Name()
+					field.ProtoPackagePath = "google.protobuf"
+					field.ProtoWellKnown = wkt
+				} else {
+					errs.Append(types.Errorf(field.Position, "unresolved type %s of field %s.%s", field.ProtoFullName, mess.Name, field.ProtoName))
+				}
+			case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+				if def, found := p.EnumDic[field.ProtoFullName]; found {
+					field.ProtoTypeName = def.Name
+					field.ProtoPackagePath = def.Package
+				} else {
+					errs.Append(types.Errorf(field.Position, "unresolved type %s of field %s.%s", field.ProtoFullName, mess.Name, field.ProtoName))
+				}
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// parsePathParams 解析路由中的 path 参数, 并关联到请求结构中的字段
+func parsePathParams(p *types.Package) error {
+	var errs types.Errors
+	for _, srv := range p.Services {
+		for _, method := range srv.Methods {
+			for _, binding := range method.Bindings {
+				template, err := parsePathTemplate(binding.Path)
+				if err != nil {
+					errs.Append(types.Errorf(method.Position, "rpc %s.%s: %v", srv.Name, method.Name, err))
+					continue
+				}
+				binding.Template = template
+
+				for _, variable := range template.Variables() {
+					param := &types.PathParam{
+						Name:    variable.Variable,
+						Field:   p.FieldByPath(method.RequestName, variable.Variable),
+						Pattern: variable.PatternString(),
+					}
+					if param.Field == nil {
+						errs.Append(types.Errorf(method.Position, "path parameter %s of rpc %s.%s not found in %s", param.Name, srv.Name, method.Name, method.RequestName))
+					}
+					binding.PathParams = append(binding.PathParams, param)
+				}
+
+				if binding.HasBody() && !binding.IsWholeBody() && p.FieldByPath(method.RequestName, binding.Body) == nil {
+					errs.Append(types.Errorf(method.Position, "body %s of rpc %s.%s not found in %s", binding.Body, srv.Name, method.Name, method.RequestName))
+				}
+				if len(binding.ResponseBody) != 0 && p.FieldByPath(method.ResponseName, binding.ResponseBody) == nil {
+					errs.Append(types.Errorf(method.Position, "response_body %s of rpc %s.%s not found in %s", binding.ResponseBody, srv.Name, method.Name, method.ResponseName))
+				}
+			}
+		}
+	}
+	return errs.Err()
 }
 
 // parseComments paarse comments in proto
-func parseComments(infor *descriptorpb.SourceCodeInfo) comments {
-	cs := make(map[string]*comment, 0)
+func parseComments(file *descriptorpb.FileDescriptorProto) comments {
+	cs := comments{
+		file:      file.GetName(),
+		locations: make(map[string]*comment, 0),
+		spans:     make(map[string][]int32, 0),
+	}
+
+	for _, location := range file.GetSourceCodeInfo().GetLocation() {
+		// 同一 path 有多个 location 时使用第一个
+		if _, found := cs.spans[fmt.Sprintf("%v", location.GetPath())]; !found {
+			cs.spans[fmt.Sprintf("%v", location.GetPath())] = location.GetSpan()
+		}
 
-	for _, location := range infor.GetLocation() {
 		if location.GetLeadingComments() == "" && location.GetTrailingComments() == "" && len(location.GetLeadingDetachedComments()) == 0 {
 			continue
 		}
 
 		detached := make([]string, 0)
 		for _, val := range location.GetLeadingDetachedComments() {
-			detached = append(detached, trim(val, "*", "\n"))
+			detached = append(detached, trimComment(val))
 		}
 
-		cs[fmt.Sprintf("%v", location.GetPath())] = &comment{
-			leading:  trim(location.GetLeadingComments(), "*", "\n"),
-			trailing: trim(location.GetTrailingComments(), "*", "\n"),
+		leading, metadata := parseDirectives(trimComment(location.GetLeadingComments()))
+		trailing, trailingMetadata := parseDirectives(trimComment(location.GetTrailingComments()))
+
+		// 没有前置注释时使用后置注释中的指令
+		if len(leading) == 0 && len(location.GetLeadingComments()) == 0 {
+			metadata = trailingMetadata
+		}
+
+		cs.locations[fmt.Sprintf("%v", location.GetPath())] = &comment{
+			leading:  leading,
+			trailing: trailing,
 			detached: detached,
+			metadata: metadata,
 		}
 	}
 	return cs
 }
 
 // parseservice parse service in proto
-func (cs comments) parseService(dsdp *descriptorpb.ServiceDescriptorProto, paths ...int) *types.Service {
+func (cs comments) parseService(dsdp *descriptorpb.ServiceDescriptorProto, pkg string, paths ...int) *types.Service {
 	var service = newService(dsdp.GetName(), cs.comment(dsdp.GetName(), paths...))
+	service.Position = cs.position(paths...)
+	service.Package = pkg
+	service.Metadata = cs.metadata(paths...)
+	service.Overview = cs.detached(paths...)
 
 	// descriptorpb.ServiceOptions
-	// if opt := parseServiceOption(dsdp.GetOptions()); opt != nil {
-	//
-	// }
+	if opt := parseServiceOption(dsdp.GetOptions()); opt != nil {
+		service.DisplayName = opt.GetName()
+		service.Host, service.Prefix = splitHost(opt.GetHost())
+		service.Authorization = types.Header(opt.GetHeader().GetAuthorization())
+	}
 
 	for idx, protoRPC := range dsdp.GetMethod() {
-		method := cs.parseMethod(protoRPC, append(paths, COMMENT_PATH_SERVICE_METHOD, idx)...)
-		if len(method.Path) == 0 {
-			method.Path = methodPath(service.Name, method.Name)
+		method := cs.parseMethod(protoRPC, commentPath(paths, COMMENT_PATH_SERVICE_METHOD, idx)...)
+		if method.Metadata.Ignore {
+			logger.Debugf("rpc %s.%s is ignored", service.Name, method.Name)
+			continue
+		}
+		if len(method.Bindings) == 0 {
+			method.Bindings = append(method.Bindings, newHttpBinding(methodPath(service.Name, method.Name), types.MethodPost, "*"))
+		}
+		if method.Consume == "" && method.HasBody() {
+			method.Consume = types.ContentTypeJson
+		}
+		if len(service.Prefix) != 0 {
+			for _, binding := range method.Bindings {
+				binding.Path = joinPath(service.Prefix, binding.Path)
+			}
 		}
 		service.Methods = append(service.Methods, method)
 	}
@@ -142,67 +415,136 @@ func (cs comments) parseService(dsdp *descriptorpb.ServiceDescriptorProto, paths
 // parseMethod parse method in service
 func (cs comments) parseMethod(dmdp *descriptorpb.MethodDescriptorProto, paths ...int) *types.ServiceMethod {
 	var method = newServiceMethod(dmdp.GetName(), cs.comment(dmdp.GetName(), paths...))
-	method.RequestName = split(dmdp.GetInputType())[1]
-	method.ResponseName = split(dmdp.GetOutputType())[1]
+	method.Position = cs.position(paths...)
+	method.Metadata = cs.metadata(paths...)
+	method.RequestName = fullName(dmdp.GetInputType())
+	method.ResponseName = fullName(dmdp.GetOutputType())
+	method.Deprecated = dmdp.GetOptions().GetDeprecated()
+
+	switch {
+	case dmdp.GetClientStreaming() && dmdp.GetServerStreaming():
+		method.Streaming = types.StreamingBidi
+	case dmdp.GetClientStreaming():
+		method.Streaming = types.StreamingClient
+	case dmdp.GetServerStreaming():
+		method.Streaming = types.StreamingServer
+	}
 
 	// descriptorpb.MethodOptions
 	if opt := parseMethodOptions(dmdp.GetOptions()); opt != nil {
+		var binding *types.HttpBinding
 		switch opt.GetPattern().(type) {
 		case *httppb.Http_Get:
-			method.Path = opt.GetGet()
-			method.Method = types.MethodGet
+			binding = newHttpBinding(opt.GetGet(), types.MethodGet, "")
 		case *httppb.Http_Put:
-			method.Path = opt.GetPut()
-			method.Method = types.MethodPut
+			binding = newHttpBinding(opt.GetPut(), types.MethodPut, "*")
 		case *httppb.Http_Post:
-			method.Path = opt.GetPost()
-			method.Method = types.MethodPost
+			binding = newHttpBinding(opt.GetPost(), types.MethodPost, "*")
 		case *httppb.Http_Delete:
-			method.Path = opt.GetDelete()
-			method.Method = types.MethodDelete
+			binding = newHttpBinding(opt.GetDelete(), types.MethodDelete, "*")
+		}
+		if binding != nil {
+			method.Bindings = append(method.Bindings, binding)
 		}
 
 		method.Consume = types.ContentType(opt.GetConsume())
 		method.Produce = types.ContentType(opt.GetProduce())
+	} else if rule := parseGoogleApiHttp(dmdp.GetOptions()); rule != nil {
+		// google.api.http
+		for _, r := range append([]*httpRule{rule}, rule.additionalBindings...) {
+			if len(r.path) != 0 {
+				binding := newHttpBinding(r.path, types.Method(strings.ToUpper(r.method)), r.body)
+				binding.ResponseBody = r.responseBody
+				method.Bindings = append(method.Bindings, binding)
+			}
+		}
 	}
 	if method.Produce == "" {
 		method.Produce = types.ContentTypeJson
 	}
-	if method.Consume == "" && method.Method != types.MethodGet {
-		method.Consume = types.ContentTypeJson
-	}
 
 	return method
 }
 
-// parseMessage parse message in proto
-func (cs comments) parseMessage(protoMessage *descriptorpb.DescriptorProto, paths ...int) *types.Message {
-	var message = newMessage(protoMessage.GetName(), cs.comment(protoMessage.GetName(), paths...))
+// parseMessageTree parse message and the messages, enums nested in it
+func (cs comments) parseMessageTree(p *types.Package, file *descriptorpb.FileDescriptorProto, protoMessage *descriptorpb.DescriptorProto, parents []string, paths ...int) {
+	var names = append(append(make([]string, 0, len(parents)+1), parents...), protoMessage.GetName())
 
-	for idx, field := range protoMessage.GetField() {
-		message.Fields = append(message.Fields, cs.parseMessageField(protoMessage, field, append(paths, COMMENT_PATH_MESSAGE_FIELD, idx)...))
+	for eidx, protoEnum := range protoMessage.GetEnumType() {
+		enum := cs.parseMessageEnum(protoEnum, file.GetPackage(), names, commentPath(paths, COMMENT_PATH_MESSAGE_ENUM, eidx)...)
+		enum.File = file.GetName()
+		p.AppendEnum(enum)
 	}
-	return message
+
+	for nidx, protoNested := range protoMessage.GetNestedType() {
+		cs.parseMessageTree(p, file, protoNested, names, commentPath(paths, COMMENT_PATH_MESSAGE_MESSAGE, nidx)...)
+	}
+
+	message := cs.parseMessage(protoMessage, file.GetPackage(), parents, paths...)
+	message.File = file.GetName()
+	p.AppendMessage(message)
 }
 
-// parseMessageNested parse message nested in message
-func (cs comments) parseMessageNested(nested *descriptorpb.DescriptorProto, parent string, paths ...int) *types.Message {
-	name := nestedName(parent, nested.GetName())
+// parseMessage parse message in proto. parents 为嵌套结构的外层结构名称
+func (cs comments) parseMessage(protoMessage *descriptorpb.DescriptorProto, pkg string, parents []string, paths ...int) *types.Message {
+	name := nestedName(append(parents, protoMessage.GetName())...)
 	var message = newMessage(name, cs.comment(name, paths...))
+	message.Position = cs.position(paths...)
+	message.Package = pkg
+	message.FullName = fullName(pkg, fullName(parents...), protoMessage.GetName())
+	message.MapEntry = protoMessage.GetOptions().GetMapEntry()
+	message.Deprecated = protoMessage.GetOptions().GetDeprecated()
+	message.Metadata = cs.metadata(paths...)
+
+	// proto3 optional 字段会生成 synthetic oneof, 不属于 oneof group
+	var synthetics = make(map[int32]struct{}, 0)
+	for _, protoField := range protoMessage.GetField() {
+		if protoField.GetProto3Optional() {
+			synthetics[protoField.GetOneofIndex()] = struct{}{}
+		}
+	}
 
-	for idx, field := range nested.GetField() {
-		message.Fields = append(message.Fields, cs.parseMessageField(nested, field, append(paths, COMMENT_PATH_MESSAGE_FIELD, idx)...))
+	var oneofs = make(map[int32]*types.MessageOneof, len(protoMessage.GetOneofDecl()))
+	for idx, oneof := range protoMessage.GetOneofDecl() {
+		if _, found := synthetics[int32(idx)]; found {
+			continue
+		}
+
+		oneofs[int32(idx)] = &types.MessageOneof{
+			Name:        oneof.GetName(),
+			Description: cs.comment(oneof.GetName(), commentPath(paths, COMMENT_PATH_MESSAGE_ONEOF, idx)...),
+		}
+		message.Oneofs = append(message.Oneofs, oneofs[int32(idx)])
+	}
+
+	for idx, protoField := range protoMessage.GetField() {
+		field := cs.parseMessageField(protoMessage, protoField, commentPath(paths, COMMENT_PATH_MESSAGE_FIELD, idx)...)
+		if field.Metadata.Ignore {
+			logger.Debugf("field %s.%s is ignored", message.Name, field.ProtoName)
+			continue
+		}
+
+		if oneof, found := oneofs[protoField.GetOneofIndex()]; found && protoField.OneofIndex != nil {
+			oneof.Fields = append(oneof.Fields, field.ProtoName)
+			field.ProtoOneof = oneof.Name
+		}
+
+		message.Fields = append(message.Fields, field)
 	}
 	return message
 }
 
 // parseMessageEnum parse enum in message
-func (cs comments) parseMessageEnum(protoEnum *descriptorpb.EnumDescriptorProto, parent string, paths ...int) *types.Enum {
-	name := nestedName(parent, protoEnum.GetName())
+func (cs comments) parseMessageEnum(protoEnum *descriptorpb.EnumDescriptorProto, pkg string, parents []string, paths ...int) *types.Enum {
+	name := nestedName(append(parents, protoEnum.GetName())...)
 	var enum = newEnum(name, cs.comment(name, paths...))
+	enum.Position = cs.position(paths...)
+	enum.Package = pkg
+	enum.FullName = fullName(pkg, fullName(parents...), protoEnum.GetName())
+	enum.Deprecated = protoEnum.GetOptions().GetDeprecated()
 
 	for idx, enumField := range protoEnum.GetValue() {
-		enum.Fields = append(enum.Fields, cs.parseEnumField(enumField, append(paths, COMMENT_PATH_ENUM_VALUE, idx)...))
+		enum.Fields = append(enum.Fields, cs.parseEnumField(enumField, commentPath(paths, COMMENT_PATH_ENUM_VALUE, idx)...))
 	}
 	return enum
 }
@@ -210,10 +552,22 @@ func (cs comments) parseMessageEnum(protoEnum *descriptorpb.EnumDescriptorProto,
 // parseMessageField parse field in message
 func (cs comments) parseMessageField(protoMessage *descriptorpb.DescriptorProto, protoField *descriptorpb.FieldDescriptorProto, paths ...int) *types.MessageField {
 	var field = &types.MessageField{MessageName: protoMessage.GetName(), Description: cs.comment(protoField.GetName(), paths...)}
+	field.Position = cs.position(paths...)
+	field.Deprecated = protoField.GetOptions().GetDeprecated()
+	field.Metadata = cs.metadata(paths...)
+	field.Rules = parseValidateRules(protoField.GetOptions())
 
 	// Json
-	field.JsonName = protoField.GetName()
+	switch conf.Get().Naming {
+	case types.NamingJson:
+		field.JsonName = jsonName(protoField)
+	default:
+		field.JsonName = protoField.GetName()
+	}
 	field.JsonLabel = types.Convert2JsonLabel(protoField.GetLabel())
+	if field.Required = isRequired(protoField, field.Rules); field.Required && field.JsonLabel == types.JsonLabel_Optional {
+		field.JsonLabel = types.JsonLabel_Required
+	}
 	field.JsonType = types.Convert2JsonType(protoField.GetType())
 	field.JsonDefaultValue = field.JsonType.DefaultValue()
 
@@ -222,14 +576,12 @@ func (cs comments) parseMessageField(protoMessage *descriptorpb.DescriptorProto,
 	field.ProtoLaber = protoField.GetLabel()
 	field.ProtoType = protoField.GetType()
 	field.ProtoNumber = protoField.GetNumber()
+	field.ProtoOptional = protoField.GetProto3Optional()
 
 	switch field.JsonType {
 	case types.JsonType_Object:
-		typename := split(protoField.GetTypeName())
-
-		field.ProtoTypeName = typename[1]
-		field.ProtoPackagePath = typename[0]
-		field.ProtoFullName = protoField.GetTypeName()
+		// ProtoTypeName、ProtoPackagePath 在所有文件解析完成后关联. see parseFieldTypes
+		field.ProtoFullName = fullName(protoField.GetTypeName())
 	case types.JsonType_Number, types.JsonType_String, types.JsonType_Boolean:
 		field.ProtoTypeName = descriptorpb.FieldDescriptorProto_Type_name[int32(field.ProtoType)]
 	}
@@ -238,8 +590,12 @@ func (cs comments) parseMessageField(protoMessage *descriptorpb.DescriptorProto,
 }
 
 // parseEnum parse enum in proto
-func (cs comments) parseEnum(protoEnum *descriptorpb.EnumDescriptorProto, paths ...int) *types.Enum {
+func (cs comments) parseEnum(protoEnum *descriptorpb.EnumDescriptorProto, pkg string, paths ...int) *types.Enum {
 	var enum = newEnum(protoEnum.GetName(), cs.comment(protoEnum.GetName(), paths...))
+	enum.Position = cs.position(paths...)
+	enum.Package = pkg
+	enum.FullName = fullName(pkg, protoEnum.GetName())
+	enum.Deprecated = protoEnum.GetOptions().GetDeprecated()
 
 	for idx, enumField := range protoEnum.GetValue() {
 		enum.Fields = append(enum.Fields, cs.parseEnumField(enumField, append(paths, COMMENT_PATH_ENUM_VALUE, idx)...))
@@ -253,6 +609,7 @@ func (cs comments) parseEnumField(protoEnumField *descriptorpb.EnumValueDescript
 		Name:        protoEnumField.GetName(),
 		Value:       protoEnumField.GetNumber(),
 		Description: cs.comment(protoEnumField.GetName(), paths...),
+		Deprecated:  protoEnumField.GetOptions().GetDeprecated(),
 	}
 }
 
@@ -269,7 +626,7 @@ func parseMethodOptions(opts *descriptorpb.MethodOptions) *httppb.Http {
 // parseServiceOption .
 func parseServiceOption(opts *descriptorpb.ServiceOptions) *servicepb.Service {
 	if opts != nil {
-		if exp, ok := proto.GetExtension(opts, httppb.E_Http).(*servicepb.Service); ok {
+		if exp, ok := proto.GetExtension(opts, servicepb.E_Srv).(*servicepb.Service); ok && exp != nil {
 			return exp
 		}
 	}
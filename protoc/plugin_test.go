@@ -0,0 +1,118 @@
+package protoc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/conf"
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestParsePathParamsErrors(t *testing.T) {
+	var binding = func(path string) *types.HttpBinding {
+		return &types.HttpBinding{Path: path, Method: types.MethodGet}
+	}
+	var p = &types.Package{Services: []*types.Service{{
+		Name: "Users",
+		Methods: []*types.ServiceMethod{
+			{Name: "List", Position: types.Position{File: "user.proto", Line: 3, Column: 3}, Bindings: []*types.HttpBinding{binding("/"), binding("/v1/users/")}},
+			{Name: "Get", Position: types.Position{File: "user.proto", Line: 5, Column: 3}, Bindings: []*types.HttpBinding{binding("/v1/users/{user_id")}},
+			{Name: "Delete", Position: types.Position{File: "user.proto", Line: 7, Column: 3}, Bindings: []*types.HttpBinding{binding("v1/users")}},
+		},
+	}}}
+
+	err := parsePathParams(p)
+	if err == nil {
+		t.Fatal("expected errors of invalid path templates")
+	}
+
+	errs, ok := err.(types.Errors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("got %v, want 2 errors", err)
+	}
+	for idx, want := range []string{"user.proto:5:3: rpc Users.Get", "user.proto:7:3: rpc Users.Delete"} {
+		if !strings.HasPrefix(errs[idx].Error(), want) {
+			t.Errorf("got error %q, want prefix %q", errs[idx], want)
+		}
+	}
+	for _, b := range p.Services[0].Methods[0].Bindings {
+		if b.Template == nil {
+			t.Errorf("%s: template not parsed", b.Path)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if err := conf.Parse(""); err != nil {
+		t.Fatal(err)
+	}
+
+	var field = func(name string, number int32, typeName string) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(typeName),
+		}
+	}
+	var req = &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"user.proto"},
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("user.proto"),
+			Package: proto.String("user"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name:  proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("profile", 1, ".user.Profile"), field("org", 2, ".org.Org")},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{Path: []int32{4, 0, 2, 0}, Span: []int32{4, 2, 24}},
+				{Path: []int32{4, 0, 2, 1}, Span: []int32{5, 2, 20}},
+			}},
+		}},
+	}
+
+	_, err := parse(req)
+	if err == nil {
+		t.Fatal("expected errors of unresolved types")
+	}
+	for _, want := range []string{
+		"user.proto:5:3: unresolved type user.Profile of field User.profile",
+		"user.proto:6:3: unresolved type org.Org of field User.org",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got %q, want error %q", err, want)
+		}
+	}
+}
+
+func TestParsePathParamsFieldErrors(t *testing.T) {
+	var p = &types.Package{
+		MessageDic: map[string]*types.Message{
+			"user.Request": {FullName: "user.Request", Fields: []*types.MessageField{{ProtoName: "user_id"}}},
+		},
+		Services: []*types.Service{{
+			Name: "Users",
+			Methods: []*types.ServiceMethod{{
+				Name:         "Update",
+				RequestName:  "user.Request",
+				ResponseName: "user.Request",
+				Bindings: []*types.HttpBinding{
+					{Path: "/v1/users/{user_id}/{name}", Method: types.MethodPatch, Body: "user", ResponseBody: "data"},
+				},
+			}},
+		}},
+	}
+
+	err := parsePathParams(p)
+	if errs, ok := err.(types.Errors); !ok || len(errs) != 3 {
+		t.Fatalf("got %v, want 3 errors", err)
+	}
+	for _, want := range []string{"path parameter name", "body user", "response_body data"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("got %q, want error containing %q", err, want)
+		}
+	}
+}
@@ -1,16 +1,26 @@
 package protoc
 
 import (
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/charlesbases/protoc-gen-apidoc/logger"
+	"github.com/charlesbases/protoc-gen-apidoc/conf"
 	"github.com/charlesbases/protoc-gen-apidoc/types"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
-// version .
+// version 文档版本. 未指定 version 参数时使用 SOURCE_DATE_EPOCH 或当前时间
 func version() string {
+	if len(conf.Get().Version) != 0 {
+		return conf.Get().Version
+	}
+
+	// reproducible builds. see https://reproducible-builds.org/specs/source-date-epoch/
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC().Format("20060102150405")
+	}
 	return time.Now().Format("20060102150405")
 }
 
@@ -19,66 +29,110 @@ func nestedName(v ...string) string {
 	return strings.Join(v, "_")
 }
 
+// jsonName protojson 字段名. protoc 未设置 json_name 时转换为 lowerCamelCase
+func jsonName(protoField *descriptorpb.FieldDescriptorProto) string {
+	if len(protoField.GetJsonName()) != 0 {
+		return protoField.GetJsonName()
+	}
+
+	var (
+		br    strings.Builder
+		upper bool
+	)
+	for _, c := range protoField.GetName() {
+		switch {
+		case c == '_':
+			upper = true
+		case upper && 'a' <= c && c <= 'z':
+			br.WriteRune(c - 'a' + 'A')
+			upper = false
+		default:
+			br.WriteRune(c)
+			upper = false
+		}
+	}
+	return br.String()
+}
+
+// commentPath 复制 paths 并追加子路径, 防止嵌套解析时共用同一个底层数组
+func commentPath(paths []int, v ...int) []int {
+	return append(append(make([]int, 0, len(paths)+len(v)), paths...), v...)
+}
+
 // methodPath .
 func methodPath(v ...string) string {
 	return "/" + strings.Join(v, "/")
 }
 
-// split split by "." and return package and type name
-func split(typename string) [2]string {
-	list := strings.Split(typename, ".")
-	if len(list) < 3 {
-		logger.Fatal("split type failed. ", typename)
+// joinPath 路由前缀与路由拼接. 例: /api/v1 + /users => /api/v1/users
+func joinPath(prefix string, path string) string {
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// splitHost 拆分服务地址与路由前缀. 例: https://api.example.com/v1 => api.example.com, /v1
+func splitHost(v string) (host string, prefix string) {
+	if idx := strings.Index(v, "://"); idx >= 0 {
+		v = v[idx+3:]
+	}
+	if idx := strings.Index(v, "/"); idx >= 0 {
+		host, prefix = v[:idx], strings.TrimSuffix(v[idx:], "/")
+	} else {
+		host = v
 	}
-	return [2]string{list[1], strings.Join(list[2:], "_")}
+	return host, prefix
 }
 
-// trim  prefix and suffix TODO 可优化
-func trim(source string, cutsets ...string) string {
-	source = strings.TrimSpace(source)
+// fullName fully-qualified proto name. 例: .user.v1.User => user.v1.User
+func fullName(v ...string) string {
+	var list = make([]string, 0, len(v))
+	for _, item := range v {
+		if item = strings.Trim(item, "."); len(item) != 0 {
+			list = append(list, item)
+		}
+	}
+	return strings.Join(list, ".")
+}
+
+// trimComment 格式化注释. 去除公共缩进, 保留多行注释的换行和相对缩进.
+// protoc 去除了块注释的 "/*"、"*/" 和每行开头的 "*", "/** ... */" 注释的首行只剩下 "*", 需要去除.
+// 其余的 "*" 为注释内容, 例: markdown 列表
+func trimComment(source string) string {
+	var lines = strings.Split(strings.TrimRight(source, " \t\r\n"), "\n")
+	for idx := range lines {
+		lines[idx] = strings.TrimRight(lines[idx], " \t\r")
+	}
 
-	for _, cutset := range cutsets {
-		for {
-			if strings.HasPrefix(source, cutset) {
-				source = strings.TrimPrefix(source, cutset)
-				continue
+	// "/**" 的首行
+	if len(lines) != 0 && strings.TrimSpace(lines[0]) == "*" {
+		lines = lines[1:]
+	}
+
+	// 公共缩进
+	var indent = -1
+	for _, line := range lines {
+		if len(strings.TrimSpace(line)) != 0 {
+			if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+				indent = n
 			}
-			break
 		}
-		for {
-			if strings.HasSuffix(source, cutset) {
-				source = strings.TrimSuffix(source, cutset)
-				continue
-			}
-			break
+	}
+	for idx, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[idx] = line[indent:]
 		}
 	}
-	return source
+
+	// 首尾空行
+	for len(lines) != 0 && len(strings.TrimSpace(lines[0])) == 0 {
+		lines = lines[1:]
+	}
+	for len(lines) != 0 && len(strings.TrimSpace(lines[len(lines)-1])) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
 }
 
 // IsEntry 是否为 proto 自动创建的 entry message. 例：map<string, string>
 func IsEntry(mf *types.MessageField) bool {
-	if mf.ProtoType == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
-		var bs strings.Builder
-		bs.Grow(len(mf.MessageName) + len(mf.ProtoName) + 6)
-
-		bs.WriteString(mf.MessageName)
-		bs.WriteString("_")
-
-		for _, item := range strings.Split(mf.ProtoName, "_") {
-			if len(item) > 0 {
-				if c := item[0]; 'a' <= c && c <= 'z' {
-					bs.WriteByte(c - ('a' - 'A'))
-				} else {
-					bs.WriteByte(c)
-				}
-				if len(item) > 1 {
-					bs.WriteString(item[1:])
-				}
-			}
-		}
-		bs.WriteString("Entry")
-		return mf.ProtoTypeName == bs.String()
-	}
-	return false
+	return mf.ProtoType == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE && mf.ProtoMapEntry
 }
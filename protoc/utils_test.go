@@ -0,0 +1,27 @@
+package protoc
+
+import "testing"
+
+func TestTrimComment(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "single line", source: " 获取用户\n", want: "获取用户"},
+		{name: "common indent", source: " first\n   indented\n second\n", want: "first\n  indented\nsecond"},
+		{name: "blank lines", source: "\n first\n\n second\n\n", want: "first\n\nsecond"},
+		{name: "markdown list", source: " * item1\n * item2\n", want: "* item1\n* item2"},
+		{name: "markdown nested list", source: " * item\n   * nested\n", want: "* item\n  * nested"},
+		{name: "javadoc", source: "*\n Get a user.\n   * by id\n ", want: "Get a user.\n  * by id"},
+		{name: "asterisk in text", source: " a * b\n *\n", want: "a * b\n*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimComment(tt.source); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package protoc
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// validation rules in FieldOptions
+//
+//	extend google.protobuf.FieldOptions {
+//	  optional validate.FieldRules rules = 1071;                 // protoc-gen-validate
+//	  optional buf.validate.FieldConstraints field = 1159;       // protovalidate
+//	}
+const (
+	validateRules    protowire.Number = 1071
+	bufValidateField protowire.Number = 1159
+)
+
+// tag numbers in validate.FieldRules and buf.validate.FieldConstraints. 两者的类型规则编号相同
+const (
+	fieldRulesFloat    protowire.Number = 1
+	fieldRulesDouble   protowire.Number = 2
+	fieldRulesInt32    protowire.Number = 3
+	fieldRulesInt64    protowire.Number = 4
+	fieldRulesUint32   protowire.Number = 5
+	fieldRulesUint64   protowire.Number = 6
+	fieldRulesSint32   protowire.Number = 7
+	fieldRulesSint64   protowire.Number = 8
+	fieldRulesFixed32  protowire.Number = 9
+	fieldRulesFixed64  protowire.Number = 10
+	fieldRulesSfixed32 protowire.Number = 11
+	fieldRulesSfixed64 protowire.Number = 12
+	fieldRulesString   protowire.Number = 14
+	fieldRulesBytes    protowire.Number = 15
+	fieldRulesEnum     protowire.Number = 16
+	fieldRulesRepeated protowire.Number = 18
+	fieldRulesMap      protowire.Number = 19
+
+	// fieldRulesMessage validate.MessageRules. required = 2
+	fieldRulesMessage protowire.Number = 17
+	// fieldRulesRequired buf.validate.FieldConstraints.required
+	fieldRulesRequired protowire.Number = 25
+)
+
+// tag numbers in numeric rules. 例: validate.Int32Rules
+const (
+	numberRulesConst protowire.Number = 1
+	numberRulesLt    protowire.Number = 2
+	numberRulesLte   protowire.Number = 3
+	numberRulesGt    protowire.Number = 4
+	numberRulesGte   protowire.Number = 5
+	numberRulesIn    protowire.Number = 6
+	numberRulesNotIn protowire.Number = 7
+)
+
+// parseValidateRules validate.rules or buf.validate.field in FieldOptions
+//
+// validate/validate.proto、buf/validate/validate.proto 未注册到当前程序中, 所以从 unknown fields 中解析
+func parseValidateRules(opts *descriptorpb.FieldOptions) *types.FieldRules {
+	if opts == nil {
+		return nil
+	}
+
+	var rules *types.FieldRules
+	rangeFields(opts.ProtoReflect().GetUnknown(), func(num protowire.Number, typ protowire.Type, data []byte) {
+		if typ != protowire.BytesType || (num != validateRules && num != bufValidateField) {
+			return
+		}
+		if rules == nil {
+			rules = new(types.FieldRules)
+		}
+		decodeFieldRules(rules, data, num == bufValidateField)
+	})
+	return rules
+}
+
+// decodeFieldRules .
+func decodeFieldRules(rules *types.FieldRules, b []byte, buf bool) {
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		switch {
+		case num == fieldRulesRequired && buf:
+			rules.Required = decodeBool(typ, data)
+		case num == fieldRulesMessage && !buf && typ == protowire.BytesType:
+			rangeFields(data, func(num protowire.Number, typ protowire.Type, data []byte) {
+				if num == 2 {
+					rules.Required = decodeBool(typ, data)
+				}
+			})
+		case num >= fieldRulesFloat && num <= fieldRulesSfixed64 && typ == protowire.BytesType:
+			decodeNumberRules(rules, num, data)
+		case num == fieldRulesString && typ == protowire.BytesType:
+			decodeStringRules(rules, data)
+		case num == fieldRulesBytes && typ == protowire.BytesType:
+			decodeBytesRules(rules, data)
+		case num == fieldRulesEnum && typ == protowire.BytesType:
+			decodeEnumRules(rules, data)
+		case num == fieldRulesRepeated && typ == protowire.BytesType:
+			decodeRepeatedRules(rules, data)
+		case num == fieldRulesMap && typ == protowire.BytesType:
+			decodeMapRules(rules, data)
+		}
+	})
+}
+
+// decodeNumberRules 数值类型规则. kind 为 FieldRules 中的类型编号
+func decodeNumberRules(rules *types.FieldRules, kind protowire.Number, b []byte) {
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		values := decodeNumbers(kind, typ, data)
+		if len(values) == 0 {
+			return
+		}
+
+		switch num {
+		case numberRulesConst:
+			rules.Const = values[0]
+		case numberRulesLt:
+			rules.Maximum, rules.ExclusiveMaximum = values[0], true
+		case numberRulesLte:
+			rules.Maximum, rules.ExclusiveMaximum = values[0], false
+		case numberRulesGt:
+			rules.Minimum, rules.ExclusiveMinimum = values[0], true
+		case numberRulesGte:
+			rules.Minimum, rules.ExclusiveMinimum = values[0], false
+		case numberRulesIn:
+			rules.In = append(rules.In, values...)
+		case numberRulesNotIn:
+			rules.NotIn = append(rules.NotIn, values...)
+		}
+	})
+}
+
+// decodeNumbers 解析数值. packed repeated 时返回多个值
+func decodeNumbers(kind protowire.Number, typ protowire.Type, b []byte) []string {
+	var values = make([]string, 0, 1)
+	for len(b) > 0 {
+		var n int
+		switch kind {
+		case fieldRulesFloat, fieldRulesFixed32, fieldRulesSfixed32:
+			var v uint32
+			if v, n = protowire.ConsumeFixed32(b); n < 0 {
+				return values
+			}
+			switch kind {
+			case fieldRulesFloat:
+				values = append(values, strconv.FormatFloat(float64(math.Float32frombits(v)), 'g', -1, 32))
+			case fieldRulesFixed32:
+				values = append(values, strconv.FormatUint(uint64(v), 10))
+			default:
+				values = append(values, strconv.FormatInt(int64(int32(v)), 10))
+			}
+		case fieldRulesDouble, fieldRulesFixed64, fieldRulesSfixed64:
+			var v uint64
+			if v, n = protowire.ConsumeFixed64(b); n < 0 {
+				return values
+			}
+			switch kind {
+			case fieldRulesDouble:
+				values = append(values, strconv.FormatFloat(math.Float64frombits(v), 'g', -1, 64))
+			case fieldRulesFixed64:
+				values = append(values, strconv.FormatUint(v, 10))
+			default:
+				values = append(values, strconv.FormatInt(int64(v), 10))
+			}
+		default:
+			var v uint64
+			if v, n = protowire.ConsumeVarint(b); n < 0 {
+				return values
+			}
+			switch kind {
+			case fieldRulesInt32:
+				values = append(values, strconv.FormatInt(int64(int32(v)), 10))
+			case fieldRulesInt64:
+				values = append(values, strconv.FormatInt(int64(v), 10))
+			case fieldRulesSint32, fieldRulesSint64:
+				values = append(values, strconv.FormatInt(protowire.DecodeZigZag(v), 10))
+			default:
+				values = append(values, strconv.FormatUint(v, 10))
+			}
+		}
+		b = b[n:]
+
+		// 非 packed 时只有一个值
+		if typ != protowire.BytesType {
+			break
+		}
+	}
+	return values
+}
+
+// decodeStringRules validate.StringRules
+func decodeStringRules(rules *types.FieldRules, b []byte) {
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		switch num {
+		case 1:
+			rules.Const = string(data)
+		case 19:
+			// len
+			rules.MinLen, rules.MaxLen = decodeUint(typ, data), decodeUint(typ, data)
+		case 2:
+			rules.MinLen = decodeUint(typ, data)
+		case 3:
+			rules.MaxLen = decodeUint(typ, data)
+		case 6:
+			rules.Pattern = string(data)
+		case 7:
+			rules.Prefix = string(data)
+		case 8:
+			rules.Suffix = string(data)
+		case 9:
+			rules.Contains = string(data)
+		case 10:
+			rules.In = append(rules.In, string(data))
+		case 11:
+			rules.NotIn = append(rules.NotIn, string(data))
+		case 12, 13, 14, 15, 16, 17, 18, 21, 22:
+			if decodeBool(typ, data) {
+				rules.Format = stringFormats[num]
+			}
+		}
+	})
+}
+
+// stringFormats well-known string formats in validate.StringRules
+var stringFormats = map[protowire.Number]string{
+	12: "email",
+	13: "hostname",
+	14: "ip",
+	15: "ipv4",
+	16: "ipv6",
+	17: "uri",
+	18: "uri-reference",
+	21: "address",
+	22: "uuid",
+}
+
+// decodeBytesRules validate.BytesRules
+func decodeBytesRules(rules *types.FieldRules, b []byte) {
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		switch num {
+		case 13:
+			// len
+			rules.MinLen, rules.MaxLen = decodeUint(typ, data), decodeUint(typ, data)
+		case 2:
+			rules.MinLen = decodeUint(typ, data)
+		case 3:
+			rules.MaxLen = decodeUint(typ, data)
+		case 4:
+			rules.Pattern = string(data)
+		}
+	})
+}
+
+// decodeEnumRules validate.EnumRules
+func decodeEnumRules(rules *types.FieldRules, b []byte) {
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		switch num {
+		case 1:
+			if values := decodeNumbers(fieldRulesInt32, typ, data); len(values) != 0 {
+				rules.Const = values[0]
+			}
+		case 2:
+			rules.DefinedOnly = decodeBool(typ, data)
+		case 3:
+			rules.In = append(rules.In, decodeNumbers(fieldRulesInt32, typ, data)...)
+		case 4:
+			rules.NotIn = append(rules.NotIn, decodeNumbers(fieldRulesInt32, typ, data)...)
+		}
+	})
+}
+
+// decodeRepeatedRules validate.RepeatedRules
+func decodeRepeatedRules(rules *types.FieldRules, b []byte) {
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		switch num {
+		case 1:
+			// min_items
+			rules.MinItems = decodeUint(typ, data)
+		case 2:
+			// max_items
+			rules.MaxItems = decodeUint(typ, data)
+		case 3:
+			// unique
+			rules.UniqueItems = decodeBool(typ, data)
+		}
+	})
+}
+
+// decodeMapRules validate.MapRules. no_sparse = 3 只对 message 类型的 value 有效, 不显示在文档中
+func decodeMapRules(rules *types.FieldRules, b []byte) {
+	rangeFields(b, func(num protowire.Number, typ protowire.Type, data []byte) {
+		switch num {
+		case 1:
+			// min_pairs
+			rules.MinItems = decodeUint(typ, data)
+		case 2:
+			// max_pairs
+			rules.MaxItems = decodeUint(typ, data)
+		}
+	})
+}
+
+// decodeBool .
+func decodeBool(typ protowire.Type, b []byte) bool {
+	if typ != protowire.VarintType {
+		return false
+	}
+	v, n := protowire.ConsumeVarint(b)
+	return n > 0 && v != 0
+}
+
+// decodeUint .
+func decodeUint(typ protowire.Type, b []byte) *uint64 {
+	if typ != protowire.VarintType {
+		return nil
+	}
+	if v, n := protowire.ConsumeVarint(b); n > 0 {
+		return &v
+	}
+	return nil
+}
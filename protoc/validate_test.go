@@ -0,0 +1,155 @@
+package protoc
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// wireVarint .
+func wireVarint(num protowire.Number, v uint64) []byte {
+	return protowire.AppendVarint(protowire.AppendTag(nil, num, protowire.VarintType), v)
+}
+
+// wireBytes .
+func wireBytes(num protowire.Number, v ...[]byte) []byte {
+	var data []byte
+	for _, item := range v {
+		data = append(data, item...)
+	}
+	return protowire.AppendBytes(protowire.AppendTag(nil, num, protowire.BytesType), data)
+}
+
+// wireString .
+func wireString(num protowire.Number, v string) []byte {
+	return wireBytes(num, []byte(v))
+}
+
+// wireFixed64 .
+func wireFixed64(num protowire.Number, v uint64) []byte {
+	return protowire.AppendFixed64(protowire.AppendTag(nil, num, protowire.Fixed64Type), v)
+}
+
+// fieldOptions FieldOptions with the extension as unknown field
+func fieldOptions(ext protowire.Number, rules ...[]byte) *descriptorpb.FieldOptions {
+	var opts = new(descriptorpb.FieldOptions)
+	opts.ProtoReflect().SetUnknown(wireBytes(ext, rules...))
+	return opts
+}
+
+// uint64p .
+func uint64p(v uint64) *uint64 {
+	return &v
+}
+
+func TestParseValidateRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  *descriptorpb.FieldOptions
+		rules *types.FieldRules
+	}{
+		{
+			name:  "no options",
+			opts:  nil,
+			rules: nil,
+		},
+		{
+			name:  "no validate rules",
+			opts:  &descriptorpb.FieldOptions{Deprecated: new(bool)},
+			rules: nil,
+		},
+		{
+			name:  "int32 gt lte",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesInt32, wireVarint(numberRulesGt, 0), wireVarint(numberRulesLte, 100))),
+			rules: &types.FieldRules{Minimum: "0", ExclusiveMinimum: true, Maximum: "100"},
+		},
+		{
+			name:  "int64 negative const",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesInt64, wireVarint(numberRulesConst, uint64(1<<64-5)))),
+			rules: &types.FieldRules{Const: "-5"},
+		},
+		{
+			name:  "sint32 gte lt",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesSint32, wireVarint(numberRulesGte, protowire.EncodeZigZag(-10)), wireVarint(numberRulesLt, protowire.EncodeZigZag(10)))),
+			rules: &types.FieldRules{Minimum: "-10", Maximum: "10", ExclusiveMaximum: true},
+		},
+		{
+			name:  "double gte",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesDouble, wireFixed64(numberRulesGte, math.Float64bits(0.5)))),
+			rules: &types.FieldRules{Minimum: "0.5"},
+		},
+		{
+			name: "uint32 in unpacked and packed",
+			opts: fieldOptions(validateRules, wireBytes(fieldRulesUint32,
+				wireVarint(numberRulesIn, 1),
+				wireBytes(numberRulesIn, protowire.AppendVarint(protowire.AppendVarint(nil, 2), 3)),
+				wireVarint(numberRulesNotIn, 4),
+			)),
+			rules: &types.FieldRules{In: []string{"1", "2", "3"}, NotIn: []string{"4"}},
+		},
+		{
+			name: "string rules",
+			opts: fieldOptions(validateRules, wireBytes(fieldRulesString,
+				wireVarint(2, 1),
+				wireVarint(3, 64),
+				wireString(6, "^[a-z]+$"),
+				wireString(7, "u_"),
+				wireVarint(12, 1),
+			)),
+			rules: &types.FieldRules{MinLen: uint64p(1), MaxLen: uint64p(64), Pattern: "^[a-z]+$", Prefix: "u_", Format: "email"},
+		},
+		{
+			name:  "string len",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesString, wireVarint(19, 8))),
+			rules: &types.FieldRules{MinLen: uint64p(8), MaxLen: uint64p(8)},
+		},
+		{
+			name:  "buf required and string in",
+			opts:  fieldOptions(bufValidateField, wireVarint(fieldRulesRequired, 1), wireBytes(fieldRulesString, wireString(10, "a"), wireString(10, "b"))),
+			rules: &types.FieldRules{Required: true, In: []string{"a", "b"}},
+		},
+		{
+			name:  "pgv message required",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesMessage, wireVarint(2, 1))),
+			rules: &types.FieldRules{Required: true},
+		},
+		{
+			name:  "pgv field 25 is not required",
+			opts:  fieldOptions(validateRules, wireVarint(fieldRulesRequired, 1)),
+			rules: &types.FieldRules{},
+		},
+		{
+			name:  "bytes rules",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesBytes, wireVarint(2, 1), wireVarint(3, 1024))),
+			rules: &types.FieldRules{MinLen: uint64p(1), MaxLen: uint64p(1024)},
+		},
+		{
+			name:  "enum rules",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesEnum, wireVarint(2, 1), wireBytes(3, protowire.AppendVarint(protowire.AppendVarint(nil, 1), 2)))),
+			rules: &types.FieldRules{DefinedOnly: true, In: []string{"1", "2"}},
+		},
+		{
+			name:  "repeated rules",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesRepeated, wireVarint(1, 1), wireVarint(2, 10), wireVarint(3, 1))),
+			rules: &types.FieldRules{MinItems: uint64p(1), MaxItems: uint64p(10), UniqueItems: true},
+		},
+		{
+			name:  "map rules no_sparse is not unique",
+			opts:  fieldOptions(validateRules, wireBytes(fieldRulesMap, wireVarint(1, 1), wireVarint(2, 5), wireVarint(3, 1))),
+			rules: &types.FieldRules{MinItems: uint64p(1), MaxItems: uint64p(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := parseValidateRules(tt.opts)
+			if !reflect.DeepEqual(rules, tt.rules) {
+				t.Errorf("got %+v, want %+v", rules, tt.rules)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
 	"sort"
+	"strings"
 	"sync"
 
 	"google.golang.org/protobuf/types/descriptorpb"
@@ -9,14 +12,17 @@ import (
 
 type (
 	Package struct {
+		srvLocker  sync.Mutex
 		enumLocker sync.RWMutex
 		messLocker sync.RWMutex
 
 		// Name Package.Name
 		Name string
+		// Packages proto package list of the generated files
+		Packages []string
 		// Version version
 		Version string
-		// Prefix uri prefix
+		// Prefix uri prefix. 所有 service 的路由前缀相同时有效
 		Prefix string
 		// Services Service list
 		Services []*Service
@@ -33,38 +39,208 @@ type (
 	Service struct {
 		Name        string
 		Description string
+		// Overview detached comments above service
+		Overview string
+		// Metadata comment directives
+		Metadata Metadata
+		// DisplayName 显示名称. servicepb name
+		DisplayName string
+		// Host 服务地址, 不包括路由前缀. servicepb host
+		Host string
+		// Prefix 路由前缀, 已添加到所有路由中. 由 servicepb host 解析, 例: api.example.com/v1 => /v1
+		Prefix string
+		// Authorization 鉴权请求头. servicepb header.authorization
+		Authorization Header
+		// Package proto package
+		Package string
+		// File proto file
+		File string
 		// Methods rpc list
 		Methods []*ServiceMethod
+		// Position position in proto file
+		Position Position
 	}
 
 	// ServiceMethod service.rpc
 	ServiceMethod struct {
-		Name         string
-		Path         string
-		Method       Method
-		Description  string
+		Name        string
+		Description string
+		// Metadata comment directives
+		Metadata     Metadata
 		Consume      ContentType
 		Produce      ContentType
 		RequestName  string
 		ResponseName string
+		// Streaming 流模式. 非流式 rpc 时为空
+		Streaming Streaming
+		// Deprecated option deprecated = true
+		Deprecated bool
+		// Bindings http 路由. Bindings[0] 为主路由, 其余为 additional_bindings
+		Bindings []*HttpBinding
+		// Position position in proto file
+		Position Position
+	}
+
+	// HttpBinding http route of service.rpc
+	HttpBinding struct {
+		Path   string
+		Method Method
+		// Template parsed path template
+		Template *PathTemplate
+		// Body request field mapped to the http body. "*" for the whole request message
+		Body string
+		// ResponseBody response field mapped to the http body. "" for the whole response message
+		ResponseBody string
+		// PathParams path 中的参数
+		PathParams []*PathParam
+	}
+
+	// PathParam path parameter of HttpBinding
+	PathParam struct {
+		// Name field path in request message. 嵌套字段以 "." 连接, 例: user.id
+		Name string
+		// Field request message field. 未找到对应字段时为 nil
+		Field *MessageField
+		// Pattern 参数匹配的路径段, 例: projects/*/books/*. 为空时匹配单个路径段
+		Pattern string
+	}
+
+	// PathTemplate google.api.http path template. 例: /v1/{name=projects/*/books/*}:publish
+	PathTemplate struct {
+		Segments []*PathSegment
+		// Verb custom verb. 例: publish
+		Verb string
+	}
+
+	// PathSegment segment of PathTemplate
+	PathSegment struct {
+		// Literal 字面量. "*" 匹配单个路径段, "**" 匹配零或多个路径段
+		Literal string
+		// Variable field path of path parameter
+		Variable string
+		// Pattern segments matched by Variable. 为空时等同于 "*"
+		Pattern []*PathSegment
 	}
 
 	Enum struct {
 		Name        string
 		Description string
 		Fields      []*EnumField
+		// Package proto package
+		Package string
+		// FullName fully-qualified proto name. 例: user.v1.User.Status
+		FullName string
+		// File proto file
+		File string
+		// Deprecated option deprecated = true
+		Deprecated bool
+		// Position position in proto file
+		Position Position
 	}
 
 	EnumField struct {
 		Name        string
 		Value       int32
 		Description string
+		// Deprecated option deprecated = true
+		Deprecated bool
 	}
 
 	Message struct {
 		Name        string
 		Description string
-		Fields      []*MessageField
+		// Metadata comment directives
+		Metadata Metadata
+		Fields   []*MessageField
+		// Package proto package
+		Package string
+		// FullName fully-qualified proto name. 例: user.v1.User
+		FullName string
+		// File proto file
+		File string
+		// MapEntry proto 自动创建的 entry message. 例：map<string, string>
+		MapEntry bool
+		// Oneofs oneof group list
+		Oneofs []*MessageOneof
+		// Deprecated option deprecated = true
+		Deprecated bool
+		// Position position in proto file
+		Position Position
+	}
+
+	// Metadata doc metadata parsed from comment directives. 例: @summary 获取用户信息
+	Metadata struct {
+		// Summary @summary 接口简介
+		Summary string
+		// Example @example 示例
+		Example string
+		// Ignore @ignore 不生成文档
+		Ignore bool
+		// Tags @tag 接口分组
+		Tags []string
+		// Since @since 起始版本
+		Since string
+		// Internal @internal 内部接口
+		Internal bool
+		// Owner @owner 负责人
+		Owner string
+	}
+
+	// FieldRules validation rules of field. 数值以字符串保存, 防止精度丢失
+	FieldRules struct {
+		// Required 字段必须设置
+		Required bool
+		// Const 固定值
+		Const string
+		// In 允许的值
+		In []string
+		// NotIn 不允许的值
+		NotIn []string
+
+		// Minimum 最小值. ExclusiveMinimum 为 true 时不包含最小值 (gt)
+		Minimum          string
+		ExclusiveMinimum bool
+		// Maximum 最大值. ExclusiveMaximum 为 true 时不包含最大值 (lt)
+		Maximum          string
+		ExclusiveMaximum bool
+
+		// MinLen string、bytes 最小长度
+		MinLen *uint64
+		// MaxLen string、bytes 最大长度
+		MaxLen *uint64
+		// Pattern 正则表达式
+		Pattern string
+		// Prefix 前缀
+		Prefix string
+		// Suffix 后缀
+		Suffix string
+		// Contains 包含的字符串
+		Contains string
+		// Format 格式. 例: email、hostname、ipv4、ipv6、uri、uuid
+		Format string
+
+		// MinItems repeated、map 最少元素个数
+		MinItems *uint64
+		// MaxItems repeated、map 最多元素个数
+		MaxItems *uint64
+		// UniqueItems repeated 元素不能重复
+		UniqueItems bool
+		// DefinedOnly enum 只能为已定义的值
+		DefinedOnly bool
+	}
+
+	// MessageOneof oneof group in message. 同一 group 中的字段最多只能设置一个
+	MessageOneof struct {
+		Name        string
+		Description string
+		// Fields proto name of the fields in group
+		Fields []string
+	}
+
+	// ServiceGroup services in the same proto package
+	ServiceGroup struct {
+		Package  string
+		Services []*Service
 	}
 
 	MessageField struct {
@@ -72,14 +248,28 @@ type (
 		MessageName string
 		// Description field description
 		Description string
+		// Deprecated option deprecated = true
+		Deprecated bool
+		// Metadata comment directives
+		Metadata Metadata
+		// Rules validation rules. validate.rules or buf.validate.field, 没有时为 nil
+		Rules *FieldRules
+		// Required 字段必须设置. proto2 required, google.api.field_behavior = REQUIRED or validation required
+		Required bool
+		// Position position in proto file
+		Position Position
 
 		ProtoName        string                                  // proto field name
 		ProtoType        descriptorpb.FieldDescriptorProto_Type  // 隐式类型
 		ProtoLaber       descriptorpb.FieldDescriptorProto_Label // proto 标签
 		ProtoTypeName    string                                  // 显示类型
-		ProtoFullName    string                                  // 包名.结构名
+		ProtoFullName    string                                  // 包名.结构名. Message.FullName or Enum.FullName
 		ProtoPackagePath string                                  // 包路径
 		ProtoNumber      int32                                   // 排序
+		ProtoMapEntry    bool                                    // 是否为 map<k, v>
+		ProtoOneof       string                                  // oneof 名称. 不属于 oneof 时为空
+		ProtoOptional    bool                                    // proto3 optional. 字段可省略, 未设置时为 null
+		ProtoWellKnown   WellKnownType                           // google.protobuf well-known type. 其他类型时为空
 
 		JsonName         string      // json field name
 		JsonType         JsonType    // json 类型
@@ -105,6 +295,14 @@ func ascending(l, r string) bool {
 	return len(l) < len(r)
 }
 
+// less 按 package、name 升序
+func less(lpkg, lname, rpkg, rname string) bool {
+	if lpkg != rpkg {
+		return ascending(lpkg, rpkg)
+	}
+	return ascending(lname, rname)
+}
+
 // descending 降序
 func descending(l, r string) bool {
 	var length int
@@ -122,11 +320,212 @@ func descending(l, r string) bool {
 	return len(l) > len(r)
 }
 
+// HasBody 是否有路由需要请求 body
+func (m *ServiceMethod) HasBody() bool {
+	for _, binding := range m.Bindings {
+		if binding.HasBody() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasBody 请求参数是否有字段映射到 body 中
+func (b *HttpBinding) HasBody() bool {
+	return len(b.Body) != 0
+}
+
+// IsWholeBody 整个请求结构映射到 body 中
+func (b *HttpBinding) IsWholeBody() bool {
+	return b.Body == "*"
+}
+
+// Variables path 参数
+func (t *PathTemplate) Variables() []*PathSegment {
+	var variables = make([]*PathSegment, 0)
+	for _, segment := range t.Segments {
+		if segment.IsVariable() {
+			variables = append(variables, segment)
+		}
+	}
+	return variables
+}
+
+// IsVariable .
+func (s *PathSegment) IsVariable() bool {
+	return len(s.Variable) != 0
+}
+
+// PatternString 参数匹配的路径段. 例: projects/*/books/*
+func (s *PathSegment) PatternString() string {
+	var list = make([]string, 0, len(s.Pattern))
+	for _, segment := range s.Pattern {
+		list = append(list, segment.Literal)
+	}
+	return strings.Join(list, "/")
+}
+
+// IsPathParam 字段是否为 path 参数
+func (b *HttpBinding) IsPathParam(name string) bool {
+	for _, param := range b.PathParams {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Oneof oneof group by name
+func (m *Message) Oneof(name string) *MessageOneof {
+	for _, oneof := range m.Oneofs {
+		if oneof.Name == name {
+			return oneof
+		}
+	}
+	return nil
+}
+
+// ExampleJson @example 的 json 表示. 非 json 格式的示例作为字符串
+func (m Metadata) ExampleJson() string {
+	if len(m.Example) == 0 || json.Valid([]byte(m.Example)) {
+		return m.Example
+	}
+
+	data, _ := json.Marshal(m.Example)
+	return string(data)
+}
+
+// Constraints 约束说明. 例: [min_len: 1 max_len: 64]
+func (r *FieldRules) Constraints() []string {
+	var list = make([]string, 0)
+	if r == nil {
+		return list
+	}
+
+	if len(r.Const) != 0 {
+		list = append(list, "const: "+r.Const)
+	}
+	if len(r.In) != 0 {
+		list = append(list, "in: ["+strings.Join(r.In, ", ")+"]")
+	}
+	if len(r.NotIn) != 0 {
+		list = append(list, "not_in: ["+strings.Join(r.NotIn, ", ")+"]")
+	}
+	if len(r.Minimum) != 0 {
+		if r.ExclusiveMinimum {
+			list = append(list, "gt: "+r.Minimum)
+		} else {
+			list = append(list, "gte: "+r.Minimum)
+		}
+	}
+	if len(r.Maximum) != 0 {
+		if r.ExclusiveMaximum {
+			list = append(list, "lt: "+r.Maximum)
+		} else {
+			list = append(list, "lte: "+r.Maximum)
+		}
+	}
+	if r.MinLen != nil {
+		list = append(list, fmt.Sprintf("min_len: %d", *r.MinLen))
+	}
+	if r.MaxLen != nil {
+		list = append(list, fmt.Sprintf("max_len: %d", *r.MaxLen))
+	}
+	if len(r.Pattern) != 0 {
+		list = append(list, "pattern: "+r.Pattern)
+	}
+	if len(r.Prefix) != 0 {
+		list = append(list, "prefix: "+r.Prefix)
+	}
+	if len(r.Suffix) != 0 {
+		list = append(list, "suffix: "+r.Suffix)
+	}
+	if len(r.Contains) != 0 {
+		list = append(list, "contains: "+r.Contains)
+	}
+	if len(r.Format) != 0 {
+		list = append(list, r.Format)
+	}
+	if r.MinItems != nil {
+		list = append(list, fmt.Sprintf("min_items: %d", *r.MinItems))
+	}
+	if r.MaxItems != nil {
+		list = append(list, fmt.Sprintf("max_items: %d", *r.MaxItems))
+	}
+	if r.UniqueItems {
+		list = append(list, "unique")
+	}
+	if r.DefinedOnly {
+		list = append(list, "defined_only")
+	}
+	return list
+}
+
+// SubPaths 字段路径中 name 的子字段路径. 例: [user.user_id, name], user => [user_id]
+func SubPaths(paths []string, name string) []string {
+	var list = make([]string, 0)
+	for _, path := range paths {
+		if strings.HasPrefix(path, name+".") {
+			list = append(list, strings.TrimPrefix(path, name+"."))
+		}
+	}
+	return list
+}
+
+// FieldByPath 根据字段路径查找结构中的字段. 嵌套字段以 "." 连接, 例: user.id
+func (p *Package) FieldByPath(messName string, path string) *MessageField {
+	var names = strings.Split(path, ".")
+	for idx, name := range names {
+		mess, found := p.MessageDic[messName]
+		if !found {
+			return nil
+		}
+
+		var field *MessageField
+		for _, item := range mess.Fields {
+			if item.ProtoName == name {
+				field = item
+				break
+			}
+		}
+
+		switch {
+		case field == nil:
+			return nil
+		case idx == len(names)-1:
+			return field
+		}
+		messName = field.ProtoFullName
+	}
+	return nil
+}
+
+// AppendService .
+func (p *Package) AppendService(def *Service) {
+	p.srvLocker.Lock()
+	p.Services = append(p.Services, def)
+	p.srvLocker.Unlock()
+}
+
+// ServiceGroups services grouped by proto package
+func (p *Package) ServiceGroups() []*ServiceGroup {
+	var groups = make([]*ServiceGroup, 0, len(p.Packages))
+	for _, srv := range p.Services {
+		if len(groups) == 0 || groups[len(groups)-1].Package != srv.Package {
+			groups = append(groups, &ServiceGroup{Package: srv.Package})
+		}
+
+		group := groups[len(groups)-1]
+		group.Services = append(group.Services, srv)
+	}
+	return groups
+}
+
 // AppendEnum .
 func (p *Package) AppendEnum(def *Enum) {
 	p.enumLocker.Lock()
-	if _, found := p.EnumDic[def.Name]; !found {
-		p.EnumDic[def.Name] = def
+	if _, found := p.EnumDic[def.FullName]; !found {
+		p.EnumDic[def.FullName] = def
 		p.Enums = append(p.Enums, def)
 	}
 	p.enumLocker.Unlock()
@@ -135,8 +534,8 @@ func (p *Package) AppendEnum(def *Enum) {
 // AppendMessage .
 func (p *Package) AppendMessage(def *Message) {
 	p.messLocker.Lock()
-	if _, found := p.MessageDic[def.Name]; !found {
-		p.MessageDic[def.Name] = def
+	if _, found := p.MessageDic[def.FullName]; !found {
+		p.MessageDic[def.FullName] = def
 		p.Messages = append(p.Messages, def)
 	}
 	p.messLocker.Unlock()
@@ -151,7 +550,7 @@ func (p *Package) Sort() *Package {
 	go func() {
 		if len(p.Services) != 0 {
 			sort.Slice(p.Services, func(i, j int) bool {
-				return ascending(p.Services[i].Name, p.Services[j].Name)
+				return less(p.Services[i].Package, p.Services[i].Name, p.Services[j].Package, p.Services[j].Name)
 			})
 		}
 
@@ -162,7 +561,7 @@ func (p *Package) Sort() *Package {
 	go func() {
 		if len(p.Messages) != 0 {
 			sort.Slice(p.Messages, func(i, j int) bool {
-				return ascending(p.Messages[i].Name, p.Messages[j].Name)
+				return less(p.Messages[i].Package, p.Messages[i].Name, p.Messages[j].Package, p.Messages[j].Name)
 			})
 		}
 
@@ -173,7 +572,7 @@ func (p *Package) Sort() *Package {
 	go func() {
 		if len(p.Enums) != 0 {
 			sort.Slice(p.Enums, func(i, j int) bool {
-				return ascending(p.Enums[i].Name, p.Enums[j].Name)
+				return less(p.Enums[i].Package, p.Enums[i].Name, p.Enums[j].Package, p.Enums[j].Name)
 			})
 		}
 
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position 在 proto 文件中的位置. 由 SourceCodeInfo 解析
+type Position struct {
+	File string
+	// Line 行号, 从 1 开始. 未知时为 0
+	Line int
+	// Column 列号, 从 1 开始. 未知时为 0
+	Column int
+}
+
+// String 例: user/v1/user.proto:12:3
+func (p Position) String() string {
+	switch {
+	case len(p.File) == 0:
+		return ""
+	case p.Line == 0:
+		return p.File
+	default:
+		return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+	}
+}
+
+// Error 带有 proto 文件位置的错误
+type Error struct {
+	Position Position
+	Err      error
+}
+
+// Errorf .
+func Errorf(pos Position, format string, v ...interface{}) *Error {
+	return &Error{Position: pos, Err: fmt.Errorf(format, v...)}
+}
+
+// Error .
+func (e *Error) Error() string {
+	if pos := e.Position.String(); len(pos) != 0 {
+		return pos + ": " + e.Err.Error()
+	}
+	return e.Err.Error()
+}
+
+// Unwrap .
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errors 错误列表. 出现错误时继续解析, 最后一起报告
+type Errors []error
+
+// Append 添加错误, 忽略 nil
+func (es *Errors) Append(errs ...error) {
+	for _, err := range errs {
+		switch e := err.(type) {
+		case nil:
+		case Errors:
+			*es = append(*es, e...)
+		default:
+			*es = append(*es, err)
+		}
+	}
+}
+
+// Err 没有错误时返回 nil
+func (es Errors) Err() error {
+	if len(es) == 0 {
+		return nil
+	}
+	return es
+}
+
+// Error 每行一个错误
+func (es Errors) Error() string {
+	var list = make([]string, 0, len(es))
+	for _, err := range es {
+		list = append(list, err.Error())
+	}
+	return strings.Join(list, "\n")
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -14,20 +16,35 @@ const (
 	DocumentTypeSwagger  DocumentType = "swagger"
 )
 
+// Naming 字段命名方式
+type Naming string
+
+const (
+	// NamingProto proto 字段名. 例: user_id
+	NamingProto Naming = "proto"
+	// NamingJson protojson 字段名, 优先使用 json_name, 默认为 lowerCamelCase. 例: userId
+	NamingJson Naming = "json"
+)
+
 type ContentType string
 
 const (
 	ContentTypeJson ContentType = "application/json"
 	ContentTypeData ContentType = "multipart/form-data"
+	// ContentTypeNdjson newline-delimited JSON. 流式请求、响应中每行为一个 json 消息
+	ContentTypeNdjson ContentType = "application/x-ndjson"
 )
 
 type Method string
 
 const (
-	MethodGet    Method = "GET"
-	MethodPut    Method = "PUT"
-	MethodPost   Method = "POST"
-	MethodDelete Method = "DELETE"
+	MethodGet     Method = "GET"
+	MethodPut     Method = "PUT"
+	MethodPost    Method = "POST"
+	MethodDelete  Method = "DELETE"
+	MethodPatch   Method = "PATCH"
+	MethodHead    Method = "HEAD"
+	MethodOptions Method = "OPTIONS"
 )
 
 // String .
@@ -37,20 +54,39 @@ func (m Method) String() string {
 
 // LowerCase .
 func (m Method) LowerCase() string {
+	return strings.ToLower(string(m))
+}
+
+// IsCustom 是否为自定义请求方式. google.api.CustomHttpPattern
+func (m Method) IsCustom() bool {
 	switch m {
-	case MethodGet:
-		return "get"
-	case MethodPut:
-		return "put"
-	case MethodPost:
-		return "post"
-	case MethodDelete:
-		return "delete"
+	case MethodGet, MethodPut, MethodPost, MethodDelete, MethodPatch, MethodHead, MethodOptions:
+		return false
 	default:
-		return ""
+		return true
 	}
 }
 
+// Streaming rpc 流模式
+type Streaming string
+
+const (
+	StreamingNone   Streaming = ""
+	StreamingClient Streaming = "client"
+	StreamingServer Streaming = "server"
+	StreamingBidi   Streaming = "bidi"
+)
+
+// IsClient 请求是否为流
+func (s Streaming) IsClient() bool {
+	return s == StreamingClient || s == StreamingBidi
+}
+
+// IsServer 响应是否为流
+func (s Streaming) IsServer() bool {
+	return s == StreamingServer || s == StreamingBidi
+}
+
 type Header string
 
 // String .
@@ -140,3 +176,13 @@ func Convert2JsonLabel(pl descriptorpb.FieldDescriptorProto_Label) JsonLabel {
 		return ""
 	}
 }
+
+// Contains .
+func Contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+// Package typestest 测试中使用的 types.Package 构造方法. 结构均位于 proto package "user" 中
+package typestest
+
+import (
+	"github.com/charlesbases/protoc-gen-apidoc/types"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Package proto package name
+const Package = "user"
+
+// NewPackage .
+func NewPackage(messages ...*types.Message) *types.Package {
+	var p = &types.Package{
+		Name:       Package,
+		Packages:   []string{Package},
+		EnumDic:    make(map[string]*types.Enum, 0),
+		MessageDic: make(map[string]*types.Message, 0),
+	}
+	for _, mess := range messages {
+		p.AppendMessage(mess)
+	}
+	return p
+}
+
+// NewMessage .
+func NewMessage(name string, fields ...*types.MessageField) *types.Message {
+	for _, field := range fields {
+		field.MessageName = name
+	}
+	return &types.Message{Name: name, Package: Package, FullName: FullName(name), Fields: fields}
+}
+
+// ScalarField string 字段
+func ScalarField(name string) *types.MessageField {
+	return &types.MessageField{
+		ProtoName:        name,
+		ProtoType:        descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		JsonName:         name,
+		JsonType:         types.JsonType_String,
+		JsonLabel:        types.JsonLabel_Optional,
+		JsonDefaultValue: types.JsonType_String.DefaultValue(),
+	}
+}
+
+// MessageField 引用 typeName 的字段
+func MessageField(name string, typeName string, label descriptorpb.FieldDescriptorProto_Label) *types.MessageField {
+	return &types.MessageField{
+		ProtoName:     name,
+		ProtoType:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		ProtoLaber:    label,
+		ProtoTypeName: typeName,
+		ProtoFullName: FullName(typeName),
+		JsonName:      name,
+		JsonType:      types.JsonType_Object,
+		JsonLabel:     types.Convert2JsonLabel(label),
+	}
+}
+
+// FullName fully-qualified name. 例: User => user.User
+func FullName(name string) string {
+	return Package + "." + name
+}
@@ -0,0 +1,60 @@
+package types
+
+import "strings"
+
+// WellKnownType google.protobuf well-known type. protojson 中使用特殊的 json 表示
+type WellKnownType string
+
+const (
+	WellKnownAny       WellKnownType = "google.protobuf.Any"
+	WellKnownDuration  WellKnownType = "google.protobuf.Duration"
+	WellKnownEmpty     WellKnownType = "google.protobuf.Empty"
+	WellKnownFieldMask WellKnownType = "google.protobuf.FieldMask"
+	WellKnownListValue WellKnownType = "google.protobuf.ListValue"
+	WellKnownStruct    WellKnownType = "google.protobuf.Struct"
+	WellKnownTimestamp WellKnownType = "google.protobuf.Timestamp"
+	WellKnownValue     WellKnownType = "google.protobuf.Value"
+
+	// wrappers
+
+	WellKnownBoolValue   WellKnownType = "google.protobuf.BoolValue"
+	WellKnownBytesValue  WellKnownType = "google.protobuf.BytesValue"
+	WellKnownDoubleValue WellKnownType = "google.protobuf.DoubleValue"
+	WellKnownFloatValue  WellKnownType = "google.protobuf.FloatValue"
+	WellKnownInt32Value  WellKnownType = "google.protobuf.Int32Value"
+	WellKnownInt64Value  WellKnownType = "google.protobuf.Int64Value"
+	WellKnownStringValue WellKnownType = "google.protobuf.StringValue"
+	WellKnownUInt32Value WellKnownType = "google.protobuf.UInt32Value"
+	WellKnownUInt64Value WellKnownType = "google.protobuf.UInt64Value"
+)
+
+// WellKnown return the WellKnownType of fully-qualified name. 非 well-known type 时返回 false
+func WellKnown(fullName string) (WellKnownType, bool) {
+	switch wkt := WellKnownType(fullName); wkt {
+	case WellKnownAny, WellKnownDuration, WellKnownEmpty, WellKnownFieldMask, WellKnownListValue, WellKnownStruct, WellKnownTimestamp, WellKnownValue,
+		WellKnownBoolValue, WellKnownBytesValue, WellKnownDoubleValue, WellKnownFloatValue, WellKnownInt32Value, WellKnownInt64Value, WellKnownStringValue, WellKnownUInt32Value, WellKnownUInt64Value:
+		return wkt, true
+	default:
+		return "", false
+	}
+}
+
+// Name 显示名称. 例: Timestamp
+func (wkt WellKnownType) Name() string {
+	return strings.TrimPrefix(string(wkt), "google.protobuf.")
+}
+
+// IsWrapper 是否为 wrappers.proto 中的包装类型. 包装类型的 json 表示为可以为 null 的基础类型
+func (wkt WellKnownType) IsWrapper() bool {
+	return strings.HasSuffix(string(wkt), "Value") && wkt != WellKnownValue && wkt != WellKnownListValue
+}
+
+// IsScalar json 表示是否为基础类型. 例: Timestamp 为 RFC 3339 string, Int64Value 为 number 或 null
+func (wkt WellKnownType) IsScalar() bool {
+	switch wkt {
+	case WellKnownTimestamp, WellKnownDuration, WellKnownFieldMask:
+		return true
+	default:
+		return wkt.IsWrapper()
+	}
+}